@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Check PR is authored by you
+func isAuthored(pr PullRequest) bool {
+	return pr.User.Login == config.Name
+}
+
+func authoredPrefix(repo string) string {
+	return fmt.Sprintf("authored_%s_", repo)
+}
+
+func authoredKey(repo string, pr PullRequest) []byte {
+	return []byte(authoredPrefix(repo) + strconv.Itoa(pr.Number))
+}
+
+// Check PR is already tracked
+// Activities on untracked PR are recorded without notification to avoid flooding
+func isTrackedAuthored(repo string, pr PullRequest) bool {
+	_, err := db.Get(authoredKey(repo, pr), nil)
+	return err == nil
+}
+
+// Start tracking your PR
+func trackAuthored(repo string, pr PullRequest) {
+	if isAuthored(pr) && !isTrackedAuthored(repo, pr) {
+		db.Put(authoredKey(repo, pr), []byte("1"), nil)
+	}
+}
+
+// Track your open PRs and check tracked PRs which are closed
+func checkAuthoredPullRequests(repo string, list []PullRequest) {
+	open := make(map[int]bool)
+	for _, pr := range list {
+		if !isAuthored(pr) {
+			continue
+		}
+		open[pr.Number] = true
+		trackAuthored(repo, pr)
+	}
+
+	prefix := authoredPrefix(repo)
+	closed := make([]int, 0)
+	iter := db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	for iter.Next() {
+		number, err := strconv.Atoi(strings.TrimPrefix(string(iter.Key()), prefix))
+		if err != nil || open[number] {
+			continue
+		}
+		closed = append(closed, number)
+	}
+	iter.Release()
+
+	for _, number := range closed {
+		checkClosedPullRequest(repo, number)
+	}
+}
+
+// Track your open PRs found by search and check tracked PRs which are closed
+// Search covers all repositories, so tracked PRs of every repository are checked
+func checkAuthoredSearchResults(items []SearchItem) {
+	open := make(map[string]bool)
+	for _, item := range items {
+		if !isAuthored(item.PullRequest) {
+			continue
+		}
+		open[string(authoredKey(item.Repo(), item.PullRequest))] = true
+		trackAuthored(item.Repo(), item.PullRequest)
+	}
+
+	closed := make([]string, 0)
+	iter := db.NewIterator(util.BytesPrefix([]byte("authored_")), nil)
+	for iter.Next() {
+		if key := string(iter.Key()); !open[key] {
+			closed = append(closed, key)
+		}
+	}
+	iter.Release()
+
+	for _, key := range closed {
+		// authored_<owner/repo>_<number>, repository name may contain underscores
+		// Other keys like authored_review_* don't have a repository
+		spec := strings.TrimPrefix(key, "authored_")
+		i := strings.LastIndex(spec, "_")
+		if i < 0 || !strings.Contains(spec[:i], "/") {
+			continue
+		}
+		number, err := strconv.Atoi(spec[i+1:])
+		if err != nil {
+			continue
+		}
+		checkClosedPullRequest(spec[:i], number)
+	}
+}
+
+// Notify tracked PR which is merged or closed by someone else
+func checkClosedPullRequest(repo string, number int) {
+	pr, err := fetchPullRequest(repo, number)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	if pr.State != "closed" {
+		return
+	}
+
+	if pr.Merged {
+		var by User
+		if pr.MergedBy != nil {
+			by = *pr.MergedBy
+		}
+		handleAuthoredClosed(repo, pr, by)
+	} else {
+		// Closer is only available in the issue
+		buf, err := sendRequest("GET", fmt.Sprintf("%s/repos/%s/issues/%d", apiBase, repo, number), nil, nil)
+		if err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return
+		}
+		var issue struct {
+			ClosedBy User `json:"closed_by"`
+		}
+		if err := json.Unmarshal(buf, &issue); err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return
+		}
+		handleAuthoredClosed(repo, pr, issue.ClosedBy)
+	}
+}
+
+// Notify your PR is merged or closed, and stop tracking it
+func handleAuthoredClosed(repo string, pr PullRequest, by User) {
+	if !isTrackedAuthored(repo, pr) {
+		return
+	}
+	db.Delete(authoredKey(repo, pr), nil)
+	if by.Login == config.Name {
+		return
+	}
+
+	event := EVENT_CLOSED
+	if pr.Merged {
+		event = EVENT_MERGED
+	}
+	logger.Notify(fmt.Sprintf("Your PR %s by %s: #%d %s", event, by.Login, pr.Number, pr.Url))
+	go notify(Notification{Type: event, Repo: repo, PullRequest: pr, Actor: by.Login})
+}
+
+// Check review is submitted by someone else
+func isAuthoredReviewActivity(r Review) bool {
+	if r.User.Login == config.Name {
+		return false
+	}
+	switch r.State {
+	case "APPROVED", "CHANGES_REQUESTED", "COMMENTED":
+		return true
+	}
+	return false
+}
+
+// Notify reviews which are submitted to your PR
+func handleAuthoredReviews(repo string, pr PullRequest, reviews []Review) {
+	tracked := isTrackedAuthored(repo, pr)
+	for _, r := range reviews {
+		if !isAuthoredReviewActivity(r) {
+			continue
+		}
+		key := []byte(fmt.Sprintf("authored_review_%d_%d", pr.Id, r.Id))
+		if _, err := db.Get(key, nil); err == nil {
+			continue
+		}
+		if tracked {
+			notifyAuthoredReview(repo, pr, r)
+		}
+		db.Put(key, []byte("1"), nil)
+	}
+}
+
+func notifyAuthoredReview(repo string, pr PullRequest, r Review) {
+	logger.Notify(fmt.Sprintf("Your PR reviewed (%s) by %s: %s", r.State, r.User.Login, r.Url))
+	go notify(Notification{Type: EVENT_REVIEW_SUBMITTED, Repo: repo, PullRequest: pr, CommentUrl: r.Url, Actor: r.User.Login, State: r.State})
+}
+
+// Notify comments which are posted to your PR
+func handleAuthoredComments(repo string, pr PullRequest, comments []Comment) {
+	tracked := isTrackedAuthored(repo, pr)
+	for _, c := range comments {
+		if c.User.Login == config.Name {
+			continue
+		}
+		key := []byte(fmt.Sprintf("authored_comment_%d_%d", pr.Id, c.Id))
+		if _, err := db.Get(key, nil); err == nil {
+			continue
+		}
+		if tracked {
+			notifyAuthoredComment(repo, pr, c)
+		}
+		db.Put(key, []byte("1"), nil)
+	}
+}
+
+func notifyAuthoredComment(repo string, pr PullRequest, c Comment) {
+	logger.Notify(fmt.Sprintf("Your PR commented by %s: %s", c.User.Login, c.Url))
+	go notify(Notification{Type: EVENT_COMMENTED, Repo: repo, PullRequest: pr, CommentUrl: c.Url, Actor: c.User.Login})
+}
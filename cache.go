@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Cached response which isn't used for this duration is evicted
+// e.g. statuses of old commits and pages of closed PRs
+const ETAG_CACHE_TTL = 24 * time.Hour
+
+// Interval to sweep expired cache
+const ETAG_CACHE_SWEEP_INTERVAL = time.Hour
+
+const ETAG_CACHE_PREFIX = "etag_"
+
+// Cached response for conditional request
+type CacheEntry struct {
+	ETag string `json:"etag"`
+	Link string `json:"link"`
+	Body []byte `json:"body"`
+	// Last used time in unix seconds
+	UsedAt int64 `json:"used_at"`
+}
+
+// Check entry isn't used for TTL
+func (e *CacheEntry) isExpired(now time.Time) bool {
+	return now.Sub(time.Unix(e.UsedAt, 0)) > ETAG_CACHE_TTL
+}
+
+// Cache statistics
+var cacheHits uint64
+var cacheMisses uint64
+
+// Last sweep time
+var cacheSweep = struct {
+	mu sync.Mutex
+	at time.Time
+}{at: time.Now()}
+
+func cacheKey(url string) []byte {
+	return []byte(ETAG_CACHE_PREFIX + url)
+}
+
+// Check request can use ETag cache
+// Requests which have their own conditional header handle 304 by themselves
+func isCacheable(method string, customHeaders map[string]string) bool {
+	if db == nil || method != "GET" {
+		return false
+	}
+	_, ok := customHeaders["If-Modified-Since"]
+	return !ok
+}
+
+// Find cached response
+func getCache(url string) (*CacheEntry, bool) {
+	v, err := db.Get(cacheKey(url), nil)
+	if err != nil {
+		return nil, false
+	}
+	entry := &CacheEntry{}
+	if err := json.Unmarshal(v, entry); err != nil || entry.isExpired(time.Now()) {
+		db.Delete(cacheKey(url), nil)
+		return nil, false
+	}
+	return entry, true
+}
+
+// Extend TTL of cached response which is used
+// Rewritten at most once per half of TTL to avoid writing on every hit
+func touchCache(url string, entry *CacheEntry) {
+	now := time.Now()
+	if now.Sub(time.Unix(entry.UsedAt, 0)) < ETAG_CACHE_TTL/2 {
+		return
+	}
+	entry.UsedAt = now.Unix()
+	if v, err := json.Marshal(entry); err == nil {
+		db.Put(cacheKey(url), v, nil)
+	}
+}
+
+// Save response which has ETag
+func putCache(url string, resp *http.Response, buf []byte) {
+	etag := resp.Header.Get("ETag")
+	if etag == "" || resp.StatusCode != 200 {
+		return
+	}
+	v, err := json.Marshal(CacheEntry{
+		ETag:   etag,
+		Link:   resp.Header.Get("Link"),
+		Body:   buf,
+		UsedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return
+	}
+	db.Put(cacheKey(url), v, nil)
+	sweepCache()
+}
+
+// Delete expired cache periodically
+func sweepCache() {
+	cacheSweep.mu.Lock()
+	defer cacheSweep.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(cacheSweep.at) < ETAG_CACHE_SWEEP_INTERVAL {
+		return
+	}
+	cacheSweep.at = now
+
+	expired := make([][]byte, 0)
+	iter := db.NewIterator(util.BytesPrefix([]byte(ETAG_CACHE_PREFIX)), nil)
+	for iter.Next() {
+		entry := &CacheEntry{}
+		if err := json.Unmarshal(iter.Value(), entry); err != nil || entry.isExpired(now) {
+			expired = append(expired, append([]byte{}, iter.Key()...))
+		}
+	}
+	iter.Release()
+
+	for _, key := range expired {
+		db.Delete(key, nil)
+	}
+	if len(expired) > 0 {
+		logger.Passive(fmt.Sprintf("ETag cache: %d expired entries are deleted", len(expired)))
+	}
+}
+
+// Log cache hit stats
+func logCacheStats() {
+	hits := atomic.LoadUint64(&cacheHits)
+	misses := atomic.LoadUint64(&cacheMisses)
+	if hits+misses == 0 {
+		return
+	}
+	logger.Passive(fmt.Sprintf(
+		"ETag cache: %d hits, %d misses (%.1f%%)",
+		hits,
+		misses,
+		float64(hits)*100/float64(hits+misses),
+	))
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheEntryIsExpired(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		usedAt   time.Time
+		expected bool
+	}{
+		{now, false},
+		{now.Add(-ETAG_CACHE_TTL + time.Minute), false},
+		{now.Add(-ETAG_CACHE_TTL - time.Minute), true},
+		// Entries which are cached by older versions
+		{time.Unix(0, 0), true},
+	}
+	for _, c := range cases {
+		entry := &CacheEntry{UsedAt: c.usedAt.Unix()}
+		if actual := entry.isExpired(now); actual != c.expected {
+			t.Errorf("isExpired() used at %s = %v, expected %v", c.usedAt, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CI states
+const (
+	CI_PENDING = "pending"
+	CI_SUCCESS = "success"
+	CI_FAILURE = "failure"
+)
+
+// Commit status
+type CommitStatus struct {
+	Context   string `json:"context"`
+	State     string `json:"state"`
+	TargetUrl string `json:"target_url"`
+}
+
+// Check run of commit
+type CheckRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	DetailsUrl string `json:"details_url"`
+	Url        string `json:"html_url"`
+}
+
+// Aggregated CI result of commit statuses and check runs
+type CIResult struct {
+	State string
+	// First failing check
+	Name string
+	Url  string
+}
+
+// Add a check result
+// failure wins over pending, and pending wins over success
+func (r *CIResult) add(state, name, url string) {
+	switch state {
+	case CI_FAILURE:
+		if r.State != CI_FAILURE {
+			r.State = CI_FAILURE
+			r.Name = name
+			r.Url = url
+		}
+	case CI_PENDING:
+		if r.State != CI_FAILURE {
+			r.State = CI_PENDING
+		}
+	case CI_SUCCESS:
+		if r.State == "" {
+			r.State = CI_SUCCESS
+		}
+	}
+}
+
+// Normalize commit status state
+func statusState(state string) string {
+	switch state {
+	case "success":
+		return CI_SUCCESS
+	case "failure", "error":
+		return CI_FAILURE
+	}
+	return CI_PENDING
+}
+
+// Normalize check run status and conclusion
+func checkRunState(run CheckRun) string {
+	if run.Status != "completed" {
+		return CI_PENDING
+	}
+	switch run.Conclusion {
+	case "success", "neutral", "skipped":
+		return CI_SUCCESS
+	}
+	return CI_FAILURE
+}
+
+// Fetch commit statuses and check runs, and aggregate them
+func fetchCIResult(repo, sha string) (CIResult, error) {
+	result := CIResult{}
+
+	buf, err := sendWrappedListRequest(fmt.Sprintf("%s/repos/%s/commits/%s/status", apiBase, repo, sha), nil, "statuses")
+	if err != nil {
+		return result, err
+	}
+	statuses := make([]CommitStatus, 0)
+	if err := json.Unmarshal(buf, &statuses); err != nil {
+		return result, err
+	}
+	for _, s := range statuses {
+		result.add(statusState(s.State), s.Context, s.TargetUrl)
+	}
+
+	buf, err = sendWrappedListRequest(fmt.Sprintf("%s/repos/%s/commits/%s/check-runs", apiBase, repo, sha), nil, "check_runs")
+	if err != nil {
+		return result, err
+	}
+	runs := make([]CheckRun, 0)
+	if err := json.Unmarshal(buf, &runs); err != nil {
+		return result, err
+	}
+	for _, run := range runs {
+		url := run.DetailsUrl
+		if url == "" {
+			url = run.Url
+		}
+		result.add(checkRunState(run), run.Name, url)
+	}
+	return result, nil
+}
+
+// Check CI of PRs which are assigned to you or authored by you
+func isCIWatched(pr PullRequest) bool {
+	return isAuthored(pr) || pr.IsAssigned(config.Name)
+}
+
+// Notify CI transition of PR head commit
+// Last state and last finished state are stored as "state:finished"
+// Changes into failure, and failure -> success (even via pending) are notified
+func checkCIStatus(repo string, pr PullRequest) {
+	if pr.Head.Sha == "" || !isCIWatched(pr) {
+		return
+	}
+	result, err := fetchCIResult(repo, pr.Head.Sha)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	// No CI is configured
+	if result.State == "" {
+		return
+	}
+
+	key := []byte(fmt.Sprintf("ci_%s_%d", repo, pr.Number))
+	v, err := db.Get(key, nil)
+	if err != nil {
+		// First seen, only record it
+		finished := ""
+		if result.State != CI_PENDING {
+			finished = result.State
+		}
+		db.Put(key, []byte(result.State+":"+finished), nil)
+		return
+	}
+
+	last := strings.SplitN(string(v), ":", 2)
+	if len(last) != 2 {
+		last = []string{"", ""}
+	}
+	if last[0] == result.State {
+		return
+	}
+	finished := last[1]
+	if result.State != CI_PENDING {
+		finished = result.State
+	}
+	db.Put(key, []byte(result.State+":"+finished), nil)
+
+	switch {
+	case result.State == CI_FAILURE:
+		logger.Notify(fmt.Sprintf("CI failed on PR: #%d %s %s", pr.Number, result.Name, result.Url))
+		go notify(Notification{Type: EVENT_CI_STATUS, Repo: repo, PullRequest: pr, State: CI_FAILURE, Check: result.Name, CommentUrl: result.Url})
+	case result.State == CI_SUCCESS && last[1] == CI_FAILURE:
+		logger.Notify(fmt.Sprintf("CI passed on PR: #%d %s", pr.Number, pr.Url))
+		go notify(Notification{Type: EVENT_CI_STATUS, Repo: repo, PullRequest: pr, State: CI_SUCCESS})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+const GITHUB_PER_PAGE = 100
+
+// GitHub Enterprise Server serves REST API under this path
+const GITHUB_ENTERPRISE_API_PATH = "/api/v3"
+
+// Resolve API and web base URL from config
+// Either can be omitted, then it is derived from the other
+func resolveBaseUrls(c *Config) (api, web string) {
+	api = strings.TrimRight(c.ApiBase, "/")
+	web = strings.TrimRight(c.WebBase, "/")
+
+	switch {
+	case api == "" && web == "":
+		return GITHUB_APIBASE, GITHUB_WEBBASE
+	case api == "":
+		if web == GITHUB_WEBBASE {
+			api = GITHUB_APIBASE
+		} else {
+			api = web + GITHUB_ENTERPRISE_API_PATH
+		}
+	case web == "":
+		if api == GITHUB_APIBASE {
+			web = GITHUB_WEBBASE
+		} else {
+			web = strings.TrimSuffix(api, GITHUB_ENTERPRISE_API_PATH)
+		}
+	}
+	return api, web
+}
+
+// Send request and return response with its body
+func doRequest(method, url string, customHeaders map[string]string, body io.Reader) (*http.Response, []byte, error) {
+	// Block while rate limited
+	resource := rateLimitResource(url)
+	rateLimit.Wait(resource)
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Attach access token
+	req.Header.Add("Authorization", "token "+config.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+	if customHeaders != nil {
+		for key, val := range customHeaders {
+			req.Header.Add(key, val)
+		}
+	}
+
+	// Conditional request with cached ETag
+	cacheable := isCacheable(method, customHeaders)
+	var cache *CacheEntry
+	if cacheable {
+		if entry, ok := getCache(url); ok {
+			cache = entry
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := rateLimit.Update(resource, resp); err != nil {
+		return resp, buf, err
+	}
+
+	if cacheable {
+		// Not modified, respond cached body
+		if resp.StatusCode == 304 && cache != nil {
+			atomic.AddUint64(&cacheHits, 1)
+			touchCache(url, cache)
+			resp.StatusCode = 200
+			resp.Header.Set("Link", cache.Link)
+			return resp, cache.Body, nil
+		}
+		atomic.AddUint64(&cacheMisses, 1)
+		putCache(url, resp, buf)
+	}
+
+	return resp, buf, nil
+}
+
+func sendRequest(method, url string, customHeaders map[string]string, body io.Reader) ([]byte, error) {
+	resp, buf, err := doRequest(method, url, customHeaders, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp, buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// Check response status is OK
+func checkResponse(resp *http.Response, buf []byte) error {
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("HTTP response failed: %d, %s", resp.StatusCode, string(buf))
+	}
+	return nil
+}
+
+// Send GET request to list endpoint and follow all pages
+// Returns JSON array which is merged all pages
+func sendListRequest(url string, customHeaders map[string]string) ([]byte, error) {
+	return sendWrappedListRequest(url, customHeaders, "")
+}
+
+// Send GET request to list endpoint whose array is wrapped in an object, and follow all pages
+// e.g. {"total_count": 1, "check_runs": [...]} with key "check_runs"
+// Returns JSON array which is merged all pages, the response itself is an array when key is empty
+func sendWrappedListRequest(url string, customHeaders map[string]string, key string) ([]byte, error) {
+	items := make([]json.RawMessage, 0)
+	next := withPerPage(url)
+	for next != "" {
+		resp, buf, err := doRequest("GET", next, customHeaders, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkResponse(resp, buf); err != nil {
+			return nil, err
+		}
+		if key != "" {
+			wrapper := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(buf, &wrapper); err != nil {
+				return nil, err
+			}
+			buf = wrapper[key]
+		}
+		page := make([]json.RawMessage, 0)
+		if len(buf) > 0 {
+			if err := json.Unmarshal(buf, &page); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, page...)
+		next = nextPageUrl(resp.Header.Get("Link"))
+	}
+	return json.Marshal(items)
+}
+
+// Add per_page parameter to get maximum items in a page
+func withPerPage(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return rawUrl
+	}
+	query := u.Query()
+	if query.Get("per_page") == "" {
+		query.Set("per_page", fmt.Sprint(GITHUB_PER_PAGE))
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// Find next page URL from Link header
+// e.g. <https://api.github.com/...?page=2>; rel="next", <https://api.github.com/...?page=5>; rel="last"
+func nextPageUrl(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNextPageUrl(t *testing.T) {
+	cases := []struct {
+		link     string
+		expected string
+	}{
+		{"", ""},
+		{
+			`<https://api.github.com/repos/o/r/pulls?page=2>; rel="next", <https://api.github.com/repos/o/r/pulls?page=5>; rel="last"`,
+			"https://api.github.com/repos/o/r/pulls?page=2",
+		},
+		{
+			`<https://api.github.com/repos/o/r/pulls?page=1>; rel="prev", <https://api.github.com/repos/o/r/pulls?page=1>; rel="first"`,
+			"",
+		},
+		{
+			`<https://api.github.com/repos/o/r/pulls?page=4>; rel="last",<https://api.github.com/repos/o/r/pulls?page=3>;rel="next"`,
+			"https://api.github.com/repos/o/r/pulls?page=3",
+		},
+		{"malformed", ""},
+	}
+	for _, c := range cases {
+		if actual := nextPageUrl(c.link); actual != c.expected {
+			t.Errorf("nextPageUrl(%q) = %q, expected %q", c.link, actual, c.expected)
+		}
+	}
+}
+
+func TestWithPerPage(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected string
+	}{
+		{"https://api.github.com/repos/o/r/pulls", "https://api.github.com/repos/o/r/pulls?per_page=100"},
+		{"https://api.github.com/notifications?all=true", "https://api.github.com/notifications?all=true&per_page=100"},
+		{"https://api.github.com/repos/o/r/pulls?per_page=10", "https://api.github.com/repos/o/r/pulls?per_page=10"},
+	}
+	for _, c := range cases {
+		if actual := withPerPage(c.url); actual != c.expected {
+			t.Errorf("withPerPage(%q) = %q, expected %q", c.url, actual, c.expected)
+		}
+	}
+}
+
+// Serve 3 pages which link to the next page
+func newTestPagesServer(wrap string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next"`, server.URL, page+1))
+		}
+		items := []int{page*10 + 1, page*10 + 2}
+		if wrap == "" {
+			json.NewEncoder(w).Encode(items)
+		} else {
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_count": 6, wrap: items})
+		}
+	}))
+	return server
+}
+
+func TestSendListRequest(t *testing.T) {
+	config = &Config{}
+	for _, wrap := range []string{"", "check_runs"} {
+		server := newTestPagesServer(wrap)
+
+		buf, err := sendWrappedListRequest(server.URL+"/items", nil, wrap)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		items := make([]int, 0)
+		if err := json.Unmarshal(buf, &items); err != nil {
+			t.Fatal(err)
+		}
+		expected := []int{11, 12, 21, 22, 31, 32}
+		if fmt.Sprint(items) != fmt.Sprint(expected) {
+			t.Errorf("Unexpected items with key %q: %v", wrap, items)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// API types to fetch pull requests
+const (
+	API_REST    = "rest"
+	API_GRAPHQL = "graphql"
+)
+
+// Fetch open pull requests with assignees, reviewers and comments at once
+const pullRequestsQuery = `
+query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: OPEN, first: 50, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        databaseId
+        number
+        title
+        url
+        body
+        headRefName
+        headRefOid
+        author { login }
+        assignees(first: 10) { nodes { databaseId login } }
+        reviewRequests(first: 20) {
+          nodes {
+            requestedReviewer {
+              __typename
+              ... on User { databaseId login }
+              ... on Team { databaseId slug }
+            }
+          }
+        }
+        comments(last: 50) { nodes { databaseId body url createdAt author { login } } }
+        reviews(last: 20) {
+          nodes {
+            databaseId
+            body
+            url
+            state
+            submittedAt
+            author { login }
+            commit { oid }
+            comments(last: 20) { nodes { databaseId body url createdAt author { login } } }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+type graphqlUser struct {
+	DatabaseId int    `json:"databaseId"`
+	Login      string `json:"login"`
+}
+
+type graphqlComment struct {
+	DatabaseId int         `json:"databaseId"`
+	Body       string      `json:"body"`
+	Url        string      `json:"url"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	Author     graphqlUser `json:"author"`
+}
+
+type graphqlPullRequest struct {
+	DatabaseId  int         `json:"databaseId"`
+	Number      int         `json:"number"`
+	Title       string      `json:"title"`
+	Url         string      `json:"url"`
+	Body        string      `json:"body"`
+	HeadRefName string      `json:"headRefName"`
+	HeadRefOid  string      `json:"headRefOid"`
+	Author      graphqlUser `json:"author"`
+	Assignees   struct {
+		Nodes []graphqlUser `json:"nodes"`
+	} `json:"assignees"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Typename   string `json:"__typename"`
+				DatabaseId int    `json:"databaseId"`
+				Login      string `json:"login"`
+				Slug       string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Comments struct {
+		Nodes []graphqlComment `json:"nodes"`
+	} `json:"comments"`
+	Reviews struct {
+		Nodes []struct {
+			DatabaseId  int         `json:"databaseId"`
+			Body        string      `json:"body"`
+			Url         string      `json:"url"`
+			State       string      `json:"state"`
+			SubmittedAt time.Time   `json:"submittedAt"`
+			Author      graphqlUser `json:"author"`
+			Commit      struct {
+				Oid string `json:"oid"`
+			} `json:"commit"`
+			Comments struct {
+				Nodes []graphqlComment `json:"nodes"`
+			} `json:"comments"`
+		} `json:"nodes"`
+	} `json:"reviews"`
+}
+
+type graphqlPullRequestsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequests struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []graphqlPullRequest `json:"nodes"`
+			} `json:"pullRequests"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphqlError `json:"errors"`
+}
+
+// GraphQL endpoint URL
+// GitHub Enterprise Server serves it at /api/graphql instead of /api/v3/graphql
+func graphqlUrl() string {
+	if strings.HasSuffix(apiBase, GITHUB_ENTERPRISE_API_PATH) {
+		return strings.TrimSuffix(apiBase, GITHUB_ENTERPRISE_API_PATH) + "/api/graphql"
+	}
+	return apiBase + "/graphql"
+}
+
+// Send GraphQL query and decode response into v
+func sendGraphqlRequest(query string, variables map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(graphqlRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return err
+	}
+	buf, err := sendRequest("POST", graphqlUrl(), nil, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// Pull request and its related data which are fetched at once
+type PullRequestDetail struct {
+	PullRequest    PullRequest
+	ReviewRequest  ReviewRequest
+	IssueComments  []Comment
+	ReviewComments []Comment
+	Reviews        []Review
+}
+
+// Convert GraphQL comment into REST type
+func (c graphqlComment) convert() Comment {
+	return Comment{
+		Id:        c.DatabaseId,
+		Body:      c.Body,
+		Url:       c.Url,
+		User:      User{Login: c.Author.Login},
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// Convert GraphQL pull request into REST types
+func (g graphqlPullRequest) convert() PullRequestDetail {
+	pr := PullRequest{
+		Id:     g.DatabaseId,
+		Title:  g.Title,
+		Number: g.Number,
+		Url:    g.Url,
+		Body:   g.Body,
+		State:  "open",
+		Head:   Ref{Ref: g.HeadRefName, Sha: g.HeadRefOid},
+		User:   User{Login: g.Author.Login},
+	}
+	for _, a := range g.Assignees.Nodes {
+		pr.Assignees = append(pr.Assignees, User{Id: a.DatabaseId, Login: a.Login})
+	}
+
+	reviews := ReviewRequest{
+		Users: []Reviewer{},
+		Teams: []ReviewTeam{},
+	}
+	for _, n := range g.ReviewRequests.Nodes {
+		r := n.RequestedReviewer
+		switch r.Typename {
+		case "User":
+			reviews.Users = append(reviews.Users, Reviewer{Id: r.DatabaseId, Name: r.Login})
+		case "Team":
+			reviews.Teams = append(reviews.Teams, ReviewTeam{Id: r.DatabaseId, Slug: r.Slug})
+		}
+	}
+
+	issueComments := make([]Comment, 0)
+	for _, c := range g.Comments.Nodes {
+		issueComments = append(issueComments, c.convert())
+	}
+	reviewComments := make([]Comment, 0)
+	submitted := make([]Review, 0)
+	for _, r := range g.Reviews.Nodes {
+		submitted = append(submitted, Review{
+			Id:          r.DatabaseId,
+			Body:        r.Body,
+			Url:         r.Url,
+			State:       r.State,
+			User:        User{Login: r.Author.Login},
+			CommitId:    r.Commit.Oid,
+			SubmittedAt: r.SubmittedAt,
+		})
+		for _, c := range r.Comments.Nodes {
+			reviewComments = append(reviewComments, c.convert())
+		}
+	}
+	return PullRequestDetail{
+		PullRequest:    pr,
+		ReviewRequest:  reviews,
+		IssueComments:  issueComments,
+		ReviewComments: reviewComments,
+		Reviews:        submitted,
+	}
+}
+
+// Fetch pull requests by GraphQL and check assigned you
+// @param repo string
+func watchPullRequestsGraphQL(repo string) {
+	logger.Passive("Watch pull requests (GraphQL): " + repo)
+
+	spec := strings.SplitN(repo, "/", 2)
+	if len(spec) != 2 {
+		logger.Error("[ERROR] Invalid repository name: " + repo)
+		return
+	}
+
+	list := make([]PullRequest, 0)
+	var cursor interface{}
+	for {
+		var resp graphqlPullRequestsResponse
+		err := sendGraphqlRequest(pullRequestsQuery, map[string]interface{}{
+			"owner":  spec[0],
+			"name":   spec[1],
+			"cursor": cursor,
+		}, &resp)
+		if err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return
+		}
+		if len(resp.Errors) > 0 {
+			logger.Error(fmt.Sprintf("[ERROR] GraphQL query failed: %s", resp.Errors[0].Message))
+			return
+		}
+
+		result := resp.Data.Repository.PullRequests
+		for _, g := range result.Nodes {
+			d := g.convert()
+			handleIssueComments(repo, d.PullRequest, d.IssueComments)
+			handleReviewComments(repo, d.PullRequest, d.ReviewComments)
+			handleReviews(repo, d.PullRequest, d.Reviews)
+			checkPullRequestBody(repo, d.PullRequest)
+			handleReviewRequests(repo, d.PullRequest, d.ReviewRequest)
+			checkAssignee(repo, d.PullRequest)
+			checkCIStatus(repo, d.PullRequest)
+			checkMergeable(repo, d.PullRequest)
+			list = append(list, d.PullRequest)
+		}
+
+		if !result.PageInfo.HasNextPage {
+			break
+		}
+		cursor = result.PageInfo.EndCursor
+	}
+	checkAuthoredPullRequests(repo, list)
+
+	logCacheStats()
+}
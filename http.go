@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTP client configuration
+type HttpConfig struct {
+	CaBundle   string `toml:"ca_bundle"`
+	ClientCert string `toml:"client_cert"`
+	ClientKey  string `toml:"client_key"`
+	Proxy      string `toml:"proxy"`
+	Timeout    int    `toml:"timeout"`
+	Insecure   bool   `toml:"insecure"`
+}
+
+// Shared HTTP client for all requests
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Create HTTP client from config
+func newHttpClient(c HttpConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+
+	if c.CaBundle != "" {
+		pem, err := ioutil.ReadFile(c.CaBundle)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No certificate found in CA bundle: %s", c.CaBundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.Insecure {
+		logger.Warn("TLS certificate verification is disabled. Don't use this mode except for testing.")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	// Use HTTP(S)_PROXY environment variables unless proxy is specified
+	proxy := http.ProxyFromEnvironment
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	timeout := 30
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+
+	return &http.Client{
+		Timeout: time.Second * time.Duration(timeout),
+		Transport: &http.Transport{
+			Proxy:               proxy,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}, nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+)
+
+// Issue data
+// Issues API also returns pull requests, they have pull_request field
+type Issue struct {
+	PullRequest
+	Labels           []Label                `json:"labels"`
+	PullRequestLinks map[string]interface{} `json:"pull_request"`
+}
+
+// Check issue is a pull request
+func (i Issue) IsPullRequest() bool {
+	return i.PullRequestLinks != nil
+}
+
+// Label data
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Per-repository watching options
+type RepositoryOption struct {
+	PullRequests *bool `toml:"pull_requests"`
+	Issues       *bool `toml:"issues"`
+}
+
+// Find watching targets of repository
+// Options keys accept wildcards like myorg/*
+func repositoryOption(repo string) (pulls, issues bool) {
+	pulls = true
+	issues = config.WatchIssues
+	for pattern, o := range config.RepositoryOptions {
+		if ok, _ := path.Match(pattern, repo); !ok {
+			continue
+		}
+		if o.PullRequests != nil {
+			pulls = *o.PullRequests
+		}
+		if o.Issues != nil {
+			issues = *o.Issues
+		}
+	}
+	return
+}
+
+// Watch pull requests and/or issues of repository
+func watchRepository(repo string) {
+	pulls, issues := repositoryOption(repo)
+	if pulls {
+		if config.Api == API_GRAPHQL {
+			watchPullRequestsGraphQL(repo)
+		} else {
+			watchPullRequests(repo)
+		}
+	}
+	if issues {
+		watchIssues(repo)
+	}
+}
+
+// Send API request and check issues which relate to you
+// @param repo string
+func watchIssues(repo string) {
+	logger.Passive("Watch issues: " + repo)
+
+	if list, err := listIssues(repo, url.Values{"assignee": {config.Name}}); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+	} else {
+		for _, issue := range list {
+			checkIssueAssignee(repo, issue)
+		}
+	}
+
+	for _, label := range config.WatchLabels {
+		if list, err := listIssues(repo, url.Values{"labels": {label}}); err != nil {
+			logger.Error("[ERROR] " + err.Error())
+		} else {
+			for _, issue := range list {
+				checkIssueLabels(repo, issue)
+			}
+		}
+	}
+
+	// Only check comments of recently updated issues
+	since := time.Now().Add(-time.Hour * 24).UTC().Format(time.RFC3339)
+	if list, err := listIssues(repo, url.Values{"mentioned": {config.Name}, "since": {since}}); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+	} else {
+		for _, issue := range list {
+			checkIssueBody(repo, issue)
+			checkIssueMentions(repo, issue)
+		}
+	}
+
+	logCacheStats()
+}
+
+// List open issues, excluding pull requests
+func listIssues(repo string, query url.Values) ([]Issue, error) {
+	query.Set("state", "open")
+	buf, err := sendListRequest(fmt.Sprintf("%s/repos/%s/issues?%s", apiBase, repo, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Issue, 0)
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0)
+	for _, issue := range list {
+		if !issue.IsPullRequest() {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// Key of assigned issue
+func issueAssignedKey(issue Issue) []byte {
+	return []byte(fmt.Sprintf("issue_%d", issue.Id))
+}
+
+// Check issue is assigned to you and notify
+func checkIssueAssignee(repo string, issue Issue) {
+	checkAssigned(issueAssignedKey(issue), issue.IsAssigned(config.Name), Notification{Type: EVENT_ISSUE_ASSIGNED, Repo: repo, PullRequest: issue.PullRequest}, "Assigned issue found")
+}
+
+// Notify watching labels on issue once
+func checkIssueLabels(repo string, issue Issue) {
+	for _, l := range issue.Labels {
+		if !isWatchedLabel(l.Name) {
+			continue
+		}
+		key := []byte(fmt.Sprintf("issue_label_%d_%s", issue.Id, l.Name))
+		if _, err := db.Get(key, nil); err != nil {
+			logger.Notify(fmt.Sprintf("Issue labeled %s: #%d %s", l.Name, issue.Number, issue.Url))
+			go notify(Notification{Type: EVENT_ISSUE_LABELED, Repo: repo, PullRequest: issue.PullRequest, Label: l.Name})
+			db.Put(key, []byte("1"), nil)
+		}
+	}
+}
+
+// Check label is in config
+func isWatchedLabel(name string) bool {
+	for _, l := range config.WatchLabels {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Check issue's description mentions you
+func checkIssueBody(repo string, issue Issue) {
+	key := []byte(fmt.Sprintf("issue_body_%d", issue.Id))
+	checkMentionedBody(key, issue.Body, Notification{Type: EVENT_ISSUE_MENTIONED, Repo: repo, PullRequest: issue.PullRequest}, "Mensioned in issue description")
+}
+
+// Check issue's comments
+func checkIssueMentions(repo string, issue Issue) {
+	logger.Passive(fmt.Sprintf("Check mensioned issue comment: %s #%d", repo, issue.Number))
+	buf, err := sendListRequest(fmt.Sprintf("%s/repos/%s/issues/%d/comments", apiBase, repo, issue.Number), nil)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	var comments = make([]Comment, 0)
+	if err := json.Unmarshal(buf, &comments); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	handleIssueMentions(repo, issue, comments)
+}
+
+// Notify issue comments which mension you
+func handleIssueMentions(repo string, issue Issue, comments []Comment) {
+	stopReminderByComments(issueAssignedKey(issue), comments)
+	for _, c := range comments {
+		if !isMentioned(c.Body) {
+			continue
+		}
+		key := []byte(fmt.Sprintf("issue_comment_%d_%d", issue.Number, c.Id))
+		if _, err := db.Get(key, nil); err != nil {
+			logger.Notify(fmt.Sprintf("Mensioned in issue: %s", c.Url))
+			go notify(Notification{Type: EVENT_ISSUE_MENTIONED, Repo: repo, PullRequest: issue.PullRequest, CommentUrl: c.Url})
+			db.Put(key, []byte("1"), nil)
+		}
+	}
+}
@@ -5,11 +5,8 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vaughan0/go-ini"
 
-	"crypto/tls"
-	"encoding/binary"
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os/exec"
 	"path/filepath"
@@ -17,24 +14,116 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
-	"io"
 	"os"
-	"strings"
 	"time"
 )
 
 // Application Configuration
 type Config struct {
-	Name           string   `toml:"name"`
-	AccessToken    string   `toml:"token"`
-	Repositories   []string `toml:"repositories"`
-	PollingTime    int      `toml:"polling"`
-	Repeat         uint64   `toml:"repeat"`
-	ApproveMessage string   `toml:"approve_message"`
+	Name                string   `toml:"name"`
+	AccessToken         string   `toml:"token"`
+	Repositories        []string `toml:"repositories"`
+	ExcludeRepositories []string `toml:"exclude_repositories"`
+	RepositoryRefresh   int      `toml:"repository_refresh"`
+	ReviewTeams         []string `toml:"review_teams"`
+	WatchIssues         bool     `toml:"watch_issues"`
+	WatchLabels         []string `toml:"watch_labels"`
+	RequiredApprovals   int      `toml:"required_approvals"`
+	PollingTime         int      `toml:"polling"`
+	Repeat              uint64   `toml:"repeat"`
+	ApproveMessage      string   `toml:"approve_message"`
+	Source              string   `toml:"source"`
+	Api                 string   `toml:"api"`
+	SearchQualifiers    []string `toml:"search_qualifiers"`
+	ApiBase             string   `toml:"api_base"`
+	WebBase             string   `toml:"web_base"`
+	Notifiers           []string `toml:"notifiers"`
+
+	Freedesktop FreedesktopConfig `toml:"freedesktop"`
+	Slack       SlackConfig       `toml:"slack"`
+	Webhook     WebhookConfig     `toml:"webhook"`
+	Email       EmailConfig       `toml:"email"`
+
+	RepositoryOptions map[string]RepositoryOption `toml:"repository_options"`
+
+	Server ServerConfig `toml:"server"`
+	Http   HttpConfig   `toml:"http"`
+
+	Reminder ReminderConfig `toml:"reminder"`
 }
 
+// Watching sources
+const (
+	SOURCE_PULLS         = "pulls"
+	SOURCE_NOTIFICATIONS = "notifications"
+	SOURCE_SEARCH        = "search"
+)
+
 // Pull Request data
 type PullRequest struct {
+	Id        int    `json:"id"`
+	Title     string `json:"title"`
+	Assignees []User `json:"assignees"`
+	User      User   `json:"user"`
+	Number    int    `json:"number"`
+	Url       string `json:"html_url"`
+	Body      string `json:"body"`
+	State     string `json:"state"`
+	Head      Ref    `json:"head"`
+	Merged    bool   `json:"merged"`
+	MergedBy  *User  `json:"merged_by"`
+	Draft     bool   `json:"draft"`
+
+	// Only available on a single PR response
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+}
+
+// Git reference of PR
+type Ref struct {
+	Ref string `json:"ref"`
+	Sha string `json:"sha"`
+}
+
+// Check user is one of assignees
+func (pr PullRequest) IsAssigned(name string) bool {
+	for _, u := range pr.Assignees {
+		if u.Login == name {
+			return true
+		}
+	}
+	return false
+}
+
+// User data
+type User struct {
+	Id    int    `json:"id"`
+	Login string `json:"login"`
+
+	// Whole object of API response for -json output
+	raw map[string]interface{}
+}
+
+func (u *User) UnmarshalJSON(b []byte) error {
+	type user User
+	var v user
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*u = User(v)
+	return json.Unmarshal(b, &u.raw)
+}
+
+// User object in -json output
+func (u User) jsonObject() map[string]interface{} {
+	if u.raw != nil {
+		return u.raw
+	}
+	return map[string]interface{}{"id": u.Id, "login": u.Login}
+}
+
+// Output of -json flag which keeps the shape of older versions
+type PullRequestJson struct {
 	Id       int                    `json:"id"`
 	Title    string                 `json:"title"`
 	Assignee map[string]interface{} `json:"assignee"`
@@ -43,6 +132,22 @@ type PullRequest struct {
 	Url      string                 `json:"html_url"`
 }
 
+// Marshal PR for -json output
+func (pr PullRequest) jsonOutput() []byte {
+	out := PullRequestJson{
+		Id:     pr.Id,
+		Title:  pr.Title,
+		User:   pr.User.jsonObject(),
+		Number: pr.Number,
+		Url:    pr.Url,
+	}
+	if len(pr.Assignees) > 0 {
+		out.Assignee = pr.Assignees[0].jsonObject()
+	}
+	buf, _ := json.Marshal(out)
+	return buf
+}
+
 // Pull Request files
 type PullRequestFile struct {
 	Filename string `json:"filename"`
@@ -51,14 +156,34 @@ type PullRequestFile struct {
 
 // Comment data
 type Comment struct {
-	Id   int    `json:"id"`
-	Body string `json:"body"`
-	Url  string `json:"html_url"`
+	Id        int       `json:"id"`
+	Body      string    `json:"body"`
+	Url       string    `json:"html_url"`
+	User      User      `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Submitted review data
+type Review struct {
+	Id          int       `json:"id"`
+	Body        string    `json:"body"`
+	Url         string    `json:"html_url"`
+	State       string    `json:"state"`
+	User        User      `json:"user"`
+	CommitId    string    `json:"commit_id"`
+	SubmittedAt time.Time `json:"submitted_at"`
 }
 
 // Reviewer data
 type ReviewRequest struct {
-	Users []Reviewer `json:"users"`
+	Users []Reviewer   `json:"users"`
+	Teams []ReviewTeam `json:"teams"`
+}
+
+// Repository data
+type Repository struct {
+	FullName string `json:"full_name"`
+	Archived bool   `json:"archived"`
 }
 
 type Reviewer struct {
@@ -66,6 +191,11 @@ type Reviewer struct {
 	Name string `json:"login"`
 }
 
+type ReviewTeam struct {
+	Id   int    `json:"id"`
+	Slug string `json:"slug"`
+}
+
 // Ansi colors
 const (
 	RED    = "\033[31m"
@@ -99,12 +229,15 @@ func (l Logger) Success(message string) {
 }
 
 const GITHUB_APIBASE = "https://api.github.com"
+const GITHUB_WEBBASE = "https://github.com"
 const GITHUB_API_LIMIT = 5000
 const CONFIG_DIR = ".github_assinee_notifiler"
 
 var db *leveldb.DB
 var config *Config
 var baseDir string
+var apiBase string
+var webBase string
 var logger Logger
 
 var isNocolor *bool
@@ -112,7 +245,9 @@ var isJson *bool
 var isSilent *bool
 var isAutomaticApprove *bool
 
-func init() {
+// Load config, or initialize it
+// Called from main() instead of init() to keep the package testable
+func loadConfig() {
 	baseDir = filepath.Join(os.Getenv("HOME"), CONFIG_DIR)
 	configPath := filepath.Join(baseDir, "config")
 	config = &Config{}
@@ -146,6 +281,8 @@ func init() {
 		}
 	}
 
+	apiBase, webBase = resolveBaseUrls(config)
+
 	// Validate config values
 	ok := true
 	if config.AccessToken == "" {
@@ -160,7 +297,7 @@ func init() {
 		)
 		ok = false
 	}
-	if len(config.Repositories) == 0 {
+	if len(config.Repositories) == 0 && config.Source != SOURCE_SEARCH {
 		logger.Error(
 			fmt.Sprintf("Watching repositories are emoty. Please open %s your editor and put 'repositories' section.", configPath),
 		)
@@ -225,6 +362,8 @@ func initializeSettings() *Config {
 }
 
 func main() {
+	loadConfig()
+
 	isNocolor = flag.Bool("nocolor", false, "No colored output")
 	isJson = flag.Bool("json", false, "Message returns JSON string")
 	isSilent = flag.Bool("silent", false, "Silent mode: stop notification, output only")
@@ -235,27 +374,44 @@ func main() {
 		logger.Warn("Automatic approve mode enabled")
 	}
 
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "summary":
-			from := "yesterday"
-			if len(os.Args) > 2 {
-				from = os.Args[2]
-			}
-			showSummary(from)
-			return
-		case "config":
-			editor := os.Getenv("EDITOR")
-			cmd := exec.Command(editor, filepath.Join(baseDir, "config"))
-			cmd.Stdout = os.Stdout
-			cmd.Stdin = os.Stdin
-			cmd.Run()
-			return
+	// Edit config before any setup which may fail by wrong config
+	// e.g. [command] config
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		editor := os.Getenv("EDITOR")
+		cmd := exec.Command(editor, filepath.Join(baseDir, "config"))
+		cmd.Stdout = os.Stdout
+		cmd.Stdin = os.Stdin
+		cmd.Run()
+		return
+	}
+
+	var err error
+	if httpClient, err = newHttpClient(config.Http); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	// One-shot command which doesn't need notifiers
+	// e.g. [command] summary
+	if len(os.Args) > 1 && os.Args[1] == "summary" {
+		from := "yesterday"
+		if len(os.Args) > 2 {
+			from = os.Args[2]
 		}
+		showSummary(from)
+		return
+	}
+
+	if err := setupNotifiers(); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	if err := setupReminder(); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
 	}
 
 	// Open LevelDB
-	var err error
 	db, err = leveldb.OpenFile(filepath.Join(baseDir, "db"), nil)
 	if err != nil {
 		logger.Error("Cannot open LevelDB. Have you already run other process?")
@@ -263,62 +419,42 @@ func main() {
 	}
 	defer db.Close()
 
-	wait := make(chan struct{}, 0)
-
-	for i, r := range config.Repositories {
-		// Loop and watch PRs in goroutine
-		watchPullRequests(r)
-		go func(duration int, repo string) {
-			time.Sleep(time.Duration(duration*10) * time.Second)
-			ticker := time.NewTicker(time.Second * time.Duration(config.PollingTime))
-			for {
-				select {
-				case <-ticker.C:
-					watchPullRequests(repo)
-				}
-			}
-		}(i, r)
+	// Receive GitHub webhook instead of polling
+	// e.g. [command] serve
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		go watchReminders()
+		if err := serve(); err != nil {
+			logger.Error("[ERROR] " + err.Error())
+		}
+		return
+	}
+
+	// Watch notifications API instead of each repository
+	if config.Source == SOURCE_NOTIFICATIONS {
+		go watchReminders()
+		watchNotifications()
+		return
+	}
+
+	// Watch search API across all repositories
+	if config.Source == SOURCE_SEARCH {
+		watchSearch()
+		return
 	}
 
 	// Blocking
-	<-wait
+	runScheduler(watchRepository)
 }
 
-func sendRequest(method, url string, customHeaders map[string]string, body io.Reader) ([]byte, error) {
-	req, err := http.NewRequest(method, url, body)
+// Fetch single pull request
+func fetchPullRequest(repo string, number int) (PullRequest, error) {
+	var pr PullRequest
+	buf, err := sendRequest("GET", fmt.Sprintf("%s/repos/%s/pulls/%d", apiBase, repo, number), nil, nil)
 	if err != nil {
-		return nil, err
+		return pr, err
 	}
-	// Attach access token
-	req.Header.Add("Authorization", "token "+config.AccessToken)
-	req.Header.Add("Content-Type", "application/json")
-	if customHeaders != nil {
-		for key, val := range customHeaders {
-			req.Header.Add(key, val)
-		}
-	}
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	buf, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP response failed: %d, %s", resp.StatusCode, string(buf))
-	}
-
-	return buf, nil
+	err = json.Unmarshal(buf, &pr)
+	return pr, err
 }
 
 // Send API reqeust and check assigned you
@@ -326,8 +462,8 @@ func sendRequest(method, url string, customHeaders map[string]string, body io.Re
 func watchPullRequests(repo string) {
 	logger.Passive("Watch pull requests: " + repo)
 
-	url := fmt.Sprintf("%s/repos/%s/pulls", GITHUB_APIBASE, repo)
-	buf, err := sendRequest("GET", url, nil, nil)
+	url := fmt.Sprintf("%s/repos/%s/pulls", apiBase, repo)
+	buf, err := sendListRequest(url, nil)
 	if err != nil {
 		logger.Error("[ERROR] " + err.Error())
 		return
@@ -343,53 +479,93 @@ func watchPullRequests(repo string) {
 	for _, pr := range list {
 		checkIssueComment(repo, pr)
 		checkReviewComment(repo, pr)
+		checkReviews(repo, pr)
+		checkPullRequestBody(repo, pr)
 		checkReviewRequests(repo, pr)
-		if login, ok := pr.Assignee["login"]; !ok || login.(string) != config.Name {
-			continue
+		checkAssignee(repo, pr)
+		checkCIStatus(repo, pr)
+		checkMergeable(repo, pr)
+	}
+	checkAuthoredPullRequests(repo, list)
+
+	logCacheStats()
+}
+
+// Check PR is assigned to you and notify
+func checkAssignee(repo string, pr PullRequest) {
+	assigned := pr.IsAssigned(config.Name)
+	if assigned && *isAutomaticApprove && pr.User.Login != config.Name {
+		if checkAndApprove(repo, pr) {
+			return
 		}
-		if *isAutomaticApprove && pr.User["login"].(string) != config.Name {
-			if checkAndApprove(repo, pr) {
-				continue
-			}
+	}
+	checkAssigned(assignedKey(pr), assigned, Notification{Type: EVENT_ASSIGNED, Repo: repo, PullRequest: pr}, "Assigned PR found")
+}
+
+// Notify assigned PR or issue, or forget it when you are unassigned
+func checkAssigned(key []byte, assigned bool, n Notification, message string) {
+	if !assigned {
+		// Unassigned, notify again when you are assigned later
+		if _, err := db.Get(key, nil); err == nil {
+			db.Delete(key, nil)
 		}
-		key := []byte(fmt.Sprint(pr.Id))
-		if v, err := db.Get(key, nil); err != nil {
-			// Didn't notify?
-			if !*isJson {
-				logger.Notify(fmt.Sprintf("Assigned PR found: #%d %s %s", pr.Number, pr.Title, pr.Url))
-				// send notification in goroutine
-				go notify(pr)
-			} else {
-				buf, _ := json.Marshal(pr)
-				logger.Notify(string(buf))
-			}
-		} else if isReNotify(v) {
-			// Need to notify repeatable?
-			if !*isJson {
-				logger.Warn(fmt.Sprintf("[REPEAT] Assigned PR found: #%d %s %s", pr.Number, pr.Title, pr.Url))
-				// send notification in goroutine
-				go notify(pr)
-			} else {
-				buf, _ := json.Marshal(pr)
-				logger.Notify(string(buf))
-			}
+		return
+	}
+	notifyAssigned(key, n, message)
+}
+
+// Key of assigned PR
+func assignedKey(pr PullRequest) []byte {
+	return []byte(fmt.Sprint(pr.Id))
+}
+
+// Notify assigned PR or issue, and remind it by the reminder policy
+func notifyAssigned(key []byte, n Notification, message string) {
+	pr := n.PullRequest
+	now := uint64(time.Now().Unix())
+	var r Reminder
+	if v, err := db.Get(key, nil); err != nil {
+		// Didn't notify?
+		r = newReminder(now)
+		if !*isJson {
+			logger.Notify(fmt.Sprintf("%s: #%d %s %s", message, pr.Number, pr.Title, pr.Url))
+			// send notification in goroutine
+			go notify(n)
 		} else {
-			continue
+			logger.Notify(string(pr.jsonOutput()))
 		}
-
-		// Save last notified timestamp
-		val := make([]byte, 8)
-		binary.LittleEndian.PutUint64(val, uint64(time.Now().Unix()))
-		db.Put(key, val, nil)
+	} else if r = decodeReminder(v); r.escalate(now) {
+		// SLA is breached?
+		if !*isJson {
+			logger.Warn(fmt.Sprintf("[ESCALATED] %s: #%d %s %s", message, pr.Number, pr.Title, pr.Url))
+			// send notification in goroutine
+			go notifyEscalation(n)
+		} else {
+			logger.Notify(string(pr.jsonOutput()))
+		}
+	} else if r.remind(now) {
+		// Need to notify repeatable?
+		if !*isJson {
+			logger.Warn(fmt.Sprintf("[REPEAT] %s: #%d %s %s", message, pr.Number, pr.Title, pr.Url))
+			// send notification in goroutine
+			n.Repeat = true
+			go notify(n)
+		} else {
+			logger.Notify(string(pr.jsonOutput()))
+		}
+	} else {
+		return
 	}
 
+	// Save reminder state
+	db.Put(key, r.encode(), nil)
 }
 
 // Check PR's review comments
 func checkReviewComment(repo string, pr PullRequest) {
 	logger.Passive("Check review comment: " + repo)
-	url := fmt.Sprintf("%s/repos/%s/pulls/%d/comments", GITHUB_APIBASE, repo, pr.Number)
-	buf, err := sendRequest("GET", url, nil, nil)
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/comments", apiBase, repo, pr.Number)
+	buf, err := sendListRequest(url, nil)
 	if err != nil {
 		logger.Error("[ERROR] " + err.Error())
 		return
@@ -401,26 +577,112 @@ func checkReviewComment(repo string, pr PullRequest) {
 		return
 	}
 
+	handleReviewComments(repo, pr, comments)
+}
+
+// Notify review comments which mension you
+func handleReviewComments(repo string, pr PullRequest, comments []Comment) {
+	if isAuthored(pr) {
+		handleAuthoredComments(repo, pr, comments)
+	}
+	stopReminderByComments(assignedKey(pr), comments)
 	for _, c := range comments {
-		if !strings.Contains(c.Body, "@"+config.Name) {
+		if !isMentioned(c.Body) {
+			continue
+		}
+		notifyMentionedReviewComment(repo, pr, c)
+	}
+}
+
+// Check PR's submitted reviews
+func checkReviews(repo string, pr PullRequest) {
+	logger.Passive("Check reviews: " + repo)
+	reviews, err := fetchReviews(repo, pr)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	handleReviews(repo, pr, reviews)
+}
+
+// Fetch PR's submitted reviews
+func fetchReviews(repo string, pr PullRequest) ([]Review, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", apiBase, repo, pr.Number)
+	buf, err := sendListRequest(url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews = make([]Review, 0)
+	if err := json.Unmarshal(buf, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// Notify review bodies which mension you
+func handleReviews(repo string, pr PullRequest, reviews []Review) {
+	if isAuthored(pr) {
+		handleAuthoredReviews(repo, pr, reviews)
+	}
+	stopReminderByReviews(pr, reviews)
+	checkNewCommits(repo, pr, reviews)
+	for _, r := range reviews {
+		if !isMentioned(r.Body) {
 			continue
 		}
-		key := []byte(fmt.Sprintf("review_%d_%d", pr.Number, c.Id))
+		key := []byte(fmt.Sprintf("review_body_%d_%d", pr.Number, r.Id))
 		if _, err := db.Get(key, nil); err != nil {
-			logger.Notify(fmt.Sprintf("Mensioned in PR: %s", c.Url))
-			go notifyComment(pr.Number, c.Url)
+			logger.Notify(fmt.Sprintf("Mensioned in PR review: %s", r.Url))
+			go notify(Notification{Type: EVENT_MENTIONED, Repo: repo, PullRequest: pr, CommentUrl: r.Url})
 			db.Put(key, []byte("1"), nil)
 		}
 	}
 }
 
+// Check PR's description mensions you
+// Notify again when the mension is added by editing after removed
+func checkPullRequestBody(repo string, pr PullRequest) {
+	key := []byte(fmt.Sprintf("body_%d", pr.Id))
+	checkMentionedBody(key, pr.Body, Notification{Type: EVENT_MENTIONED, Repo: repo, PullRequest: pr}, "Mensioned in PR description")
+}
+
+// Notify description of PR or issue which mensions you
+// Mentioned state is stored as "0" or "1" to notify again when the mension is added by editing after removed
+func checkMentionedBody(key []byte, body string, n Notification, message string) {
+	state := "0"
+	if isMentioned(body) {
+		state = "1"
+	}
+	v, err := db.Get(key, nil)
+	if err == nil && string(v) == state {
+		return
+	}
+	if state == "1" {
+		logger.Notify(fmt.Sprintf("%s: #%d %s", message, n.PullRequest.Number, n.PullRequest.Url))
+		go notify(n)
+	}
+	db.Put(key, []byte(state), nil)
+}
+
+// Notify mensioned review comment once
+func notifyMentionedReviewComment(repo string, pr PullRequest, c Comment) {
+	key := []byte(fmt.Sprintf("review_%d_%d", pr.Number, c.Id))
+	if _, err := db.Get(key, nil); err != nil {
+		logger.Notify(fmt.Sprintf("Mensioned in PR: %s", c.Url))
+		go notify(Notification{Type: EVENT_MENTIONED, Repo: repo, PullRequest: pr, CommentUrl: c.Url})
+		db.Put(key, []byte("1"), nil)
+	}
+}
+
 // Check PR's comments
 func checkIssueComment(repo string, pr PullRequest) {
 	logger.Passive("Check mensioned comment: " + repo)
-	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", GITHUB_APIBASE, repo, pr.Number)
-	buf, err := sendRequest("GET", url, map[string]string{
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", apiBase, repo, pr.Number)
+	buf, err := sendListRequest(url, map[string]string{
 		"Accept": "application/vnd.github.black-cat-preview+json",
-	}, nil)
+	})
 	if err != nil {
 		logger.Error("[ERROR] " + err.Error())
 		return
@@ -432,23 +694,37 @@ func checkIssueComment(repo string, pr PullRequest) {
 		return
 	}
 
+	handleIssueComments(repo, pr, comments)
+}
+
+// Notify issue comments which mension you
+func handleIssueComments(repo string, pr PullRequest, comments []Comment) {
+	if isAuthored(pr) {
+		handleAuthoredComments(repo, pr, comments)
+	}
+	stopReminderByComments(assignedKey(pr), comments)
 	for _, c := range comments {
-		if !strings.Contains(c.Body, "@"+config.Name) {
+		if !isMentioned(c.Body) {
 			continue
 		}
-		key := []byte(fmt.Sprintf("comment_%d_%d", pr.Number, c.Id))
-		if _, err := db.Get(key, nil); err != nil {
-			logger.Notify(fmt.Sprintf("Mensioned in PR issue: %s", c.Url))
-			go notifyComment(pr.Number, c.Url)
-			db.Put(key, []byte("1"), nil)
-		}
+		notifyMentionedIssueComment(repo, pr, c)
+	}
+}
+
+// Notify mensioned issue comment once
+func notifyMentionedIssueComment(repo string, pr PullRequest, c Comment) {
+	key := []byte(fmt.Sprintf("comment_%d_%d", pr.Number, c.Id))
+	if _, err := db.Get(key, nil); err != nil {
+		logger.Notify(fmt.Sprintf("Mensioned in PR issue: %s", c.Url))
+		go notify(Notification{Type: EVENT_MENTIONED, Repo: repo, PullRequest: pr, CommentUrl: c.Url})
+		db.Put(key, []byte("1"), nil)
 	}
 }
 
 // Check you added as reviewer
 func checkReviewRequests(repo string, pr PullRequest) {
 	logger.Passive("Check review request: " + repo)
-	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", GITHUB_APIBASE, repo, pr.Number)
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", apiBase, repo, pr.Number)
 	buf, err := sendRequest("GET", url, map[string]string{
 		"Accept": "application/vnd.github.black-cat-preview+json",
 	}, nil)
@@ -464,6 +740,11 @@ func checkReviewRequests(repo string, pr PullRequest) {
 		return
 	}
 
+	handleReviewRequests(repo, pr, reviews)
+}
+
+// Notify if you are requested as reviewer
+func handleReviewRequests(repo string, pr PullRequest, reviews ReviewRequest) {
 	for _, r := range reviews.Users {
 		if r.Name != config.Name {
 			continue
@@ -471,112 +752,27 @@ func checkReviewRequests(repo string, pr PullRequest) {
 		key := []byte(fmt.Sprintf("reviewer_%d_%d", pr.Number, r.Id))
 		if _, err := db.Get(key, nil); err != nil {
 			logger.Notify(fmt.Sprintf("You added as reviewer in PR: #%d", pr.Number))
-			go notifyReviewer(pr)
+			go notify(Notification{Type: EVENT_REVIEW_REQUESTED, Repo: repo, PullRequest: pr})
 			db.Put(key, []byte("1"), nil)
 		}
 	}
-}
-
-// Check PR should notify
-func isReNotify(v []byte) (is bool) {
-	now := uint64(time.Now().Unix())
-	last := binary.LittleEndian.Uint64(v)
 
-	if last+config.Repeat < now {
-		is = true
+	if len(reviews.Teams) == 0 {
+		return
+	}
+	teams := reviewTeams()
+	for _, r := range reviews.Teams {
+		team, ok := teams[r.Id]
+		if !ok {
+			continue
+		}
+		key := []byte(fmt.Sprintf("team_reviewer_%s_%d_%d", repo, pr.Number, r.Id))
+		if _, err := db.Get(key, nil); err != nil {
+			logger.Notify(fmt.Sprintf("Your team @%s added as reviewer in PR: #%d", team.FullName(), pr.Number))
+			go notify(Notification{Type: EVENT_TEAM_REVIEW_REQUESTED, Repo: repo, PullRequest: pr, Team: team.FullName()})
+			db.Put(key, []byte("1"), nil)
+		}
 	}
-
-	return
-}
-
-// Send notification
-func notify(pr PullRequest) error {
-	if *isSilent {
-		return nil
-	}
-	args := []string{
-		"-title",
-		fmt.Sprintf("New Pull Request Assigned: #%d", pr.Number),
-		"-subtitle",
-		pr.Title,
-		"-timeout",
-		"300",
-		"-open",
-		pr.Url,
-		"-message",
-		pr.Url,
-		"-appIcon",
-		filepath.Join(baseDir, "icon.png"),
-	}
-
-	return exec.Command("terminal-notifier", args...).Run()
-}
-
-// Send notification for comment
-func notifyComment(prId int, url string) error {
-	if *isSilent {
-		return nil
-	}
-	args := []string{
-		"-title",
-		fmt.Sprintf("Mensioned in PR: #%d", prId),
-		"-timeout",
-		"300",
-		"-open",
-		url,
-		"-message",
-		url,
-		"-appIcon",
-		filepath.Join(baseDir, "icon.png"),
-	}
-
-	return exec.Command("terminal-notifier", args...).Run()
-}
-
-// Send reviewer notification
-func notifyReviewer(pr PullRequest) error {
-	if *isSilent {
-		return nil
-	}
-	args := []string{
-		"-title",
-		fmt.Sprintf("You added reviewer: #%d", pr.Number),
-		"-subtitle",
-		pr.Title,
-		"-timeout",
-		"300",
-		"-open",
-		pr.Url,
-		"-message",
-		pr.Url,
-		"-appIcon",
-		filepath.Join(baseDir, "icon.png"),
-	}
-
-	return exec.Command("terminal-notifier", args...).Run()
-}
-
-// Send automatic approved PR
-func notifyAutomaticApprove(pr PullRequest) error {
-	if *isSilent {
-		return nil
-	}
-	args := []string{
-		"-title",
-		fmt.Sprintf("PR has approved automatically: #%d", pr.Number),
-		"-subtitle",
-		pr.Title,
-		"-timeout",
-		"300",
-		"-open",
-		pr.Url,
-		"-message",
-		pr.Url,
-		"-appIcon",
-		filepath.Join(baseDir, "icon.png"),
-	}
-
-	return exec.Command("terminal-notifier", args...).Run()
 }
 
 // Show summary
@@ -597,23 +793,7 @@ func showSummary(from string) {
 	t = t.Add(-time.Hour * 9)
 	query := url.Values{}
 	query.Add("since", t.Format("2006-01-02T15:00:00Z"))
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/notifications?%s", GITHUB_APIBASE, query.Encode()), nil)
-	if err != nil {
-		logger.Error("[ERROR] " + err.Error())
-		return
-	}
-	// Attach access token
-	req.Header.Add("Authorization", "token "+config.AccessToken)
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
-	buf, err := ioutil.ReadAll(resp.Body)
+	buf, err := sendListRequest(fmt.Sprintf("%s/notifications?%s", apiBase, query.Encode()), nil)
 	if err != nil {
 		logger.Error("[ERROR] " + err.Error())
 		return
@@ -639,7 +819,7 @@ func checkAutoApproveFiles(prFiles []PullRequestFile) (ok bool) {
 
 // Check pull request files and approve if bunmping version only
 func checkAndApprove(repo string, pr PullRequest) bool {
-	buf, err := sendRequest("GET", fmt.Sprintf("%s/repos/%s/pulls/%d/files", GITHUB_APIBASE, repo, pr.Number), nil, nil)
+	buf, err := sendListRequest(fmt.Sprintf("%s/repos/%s/pulls/%d/files", apiBase, repo, pr.Number), nil)
 	if err != nil {
 		logger.Error("[ERROR] " + err.Error())
 		return false
@@ -669,7 +849,7 @@ func checkAndApprove(repo string, pr PullRequest) bool {
 	}
 
 	logger.Notify(fmt.Sprintf("Automatic PR approved #%d", pr.Number))
-	go notifyAutomaticApprove(pr)
+	go notify(Notification{Type: EVENT_AUTO_APPROVED, Repo: repo, PullRequest: pr})
 	return true
 }
 
@@ -685,7 +865,7 @@ func sendApproveRequest(repo string, pr PullRequest) error {
 	b, _ := json.Marshal(postBody)
 	_, err := sendRequest(
 		"POST",
-		fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", GITHUB_APIBASE, repo, pr.Number),
+		fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", apiBase, repo, pr.Number),
 		map[string]string{"Accept": "application/vnd.github.black-cat-preview+json"},
 		bytes.NewReader(b),
 	)
@@ -694,14 +874,13 @@ func sendApproveRequest(repo string, pr PullRequest) error {
 }
 
 func updatePullRequestAssignee(repo string, pr PullRequest) error {
-	userName, _ := pr.User["login"].(string)
 	patchBody := map[string]interface{}{
-		"assignees": []string{userName},
+		"assignees": []string{pr.User.Login},
 	}
 	b, _ := json.Marshal(patchBody)
 	_, err := sendRequest(
 		"PATCH",
-		fmt.Sprintf("%s/repos/%s/issues/%d", GITHUB_APIBASE, repo, pr.Number),
+		fmt.Sprintf("%s/repos/%s/issues/%d", apiBase, repo, pr.Number),
 		nil,
 		bytes.NewReader(b),
 	)
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPullRequestJsonOutput(t *testing.T) {
+	var pr PullRequest
+	body := `{
+		"id": 1,
+		"title": "Fix something",
+		"number": 42,
+		"html_url": "https://github.com/owner/repo/pull/42",
+		"user": {"id": 10, "login": "author", "type": "User"},
+		"assignees": [{"id": 20, "login": "you", "type": "User"}]
+	}`
+	if err := json.Unmarshal([]byte(body), &pr); err != nil {
+		t.Fatal(err)
+	}
+	if pr.User.Login != "author" || pr.Assignees[0].Login != "you" {
+		t.Fatalf("Unexpected users: %+v", pr)
+	}
+
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(pr.jsonOutput(), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"id", "title", "assignee", "user", "number", "html_url"} {
+		if _, ok := out[key]; !ok {
+			t.Errorf("%s is missing in %v", key, out)
+		}
+	}
+	if out["user"].(map[string]interface{})["type"] != "User" {
+		t.Errorf("User object must keep whole keys: %v", out["user"])
+	}
+	if out["assignee"].(map[string]interface{})["login"] != "you" {
+		t.Errorf("Unexpected assignee: %v", out["assignee"])
+	}
+}
+
+func TestPullRequestJsonOutputWithoutAssignee(t *testing.T) {
+	pr := PullRequest{Id: 1, Number: 42, User: User{Id: 10, Login: "author"}}
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(pr.jsonOutput(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["assignee"] != nil {
+		t.Errorf("Assignee must be null: %v", out["assignee"])
+	}
+	if out["user"].(map[string]interface{})["login"] != "author" {
+		t.Errorf("Unexpected user: %v", out["user"])
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// @login or @org/team-slug, which is not a part of word like email address
+var mentionPattern = regexp.MustCompile(
+	"(?:^|[^\\w@/`])@([A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38})(?:/([A-Za-z0-9][A-Za-z0-9_-]*))?",
+)
+
+// Mentions in the text
+type Mentions struct {
+	Users map[string]bool
+	Teams map[string]bool
+}
+
+// Check body mentions you or your teams
+func isMentioned(body string) bool {
+	m := parseMentions(body)
+	if m.Users[strings.ToLower(config.Name)] {
+		return true
+	}
+	if len(m.Teams) == 0 {
+		return false
+	}
+	for _, t := range myTeams() {
+		if m.Teams[strings.ToLower(t.FullName())] {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse mentions in markdown text
+// Fenced and indented code blocks, inline code and quoted lines are ignored
+func parseMentions(body string) Mentions {
+	m := Mentions{
+		Users: make(map[string]bool),
+		Teams: make(map[string]bool),
+	}
+
+	fence := ""
+	// Indented code block starts after a blank line, it can't interrupt a paragraph
+	blank := true
+	indented := false
+	for _, line := range strings.Split(strings.Replace(body, "\r\n", "\n", -1), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			blank = true
+			continue
+		}
+		if fence == "" && isIndentedCode(line) && (blank || indented) {
+			indented = true
+			blank = false
+			continue
+		}
+		blank = false
+		indented = false
+
+		// Enter or leave fenced code block
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			fence = "```"
+			continue
+		}
+		if strings.HasPrefix(trimmed, "~~~") {
+			fence = "~~~"
+			continue
+		}
+
+		// Quoted reply
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+
+		for _, text := range stripInlineCode(line) {
+			findMentions(text, m)
+		}
+	}
+	return m
+}
+
+// Check line is indented by 4 spaces or a tab
+func isIndentedCode(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// Split line by inline code spans and return texts out of them
+// Code span is closed by a backtick string of the same length, so double backticks can contain a single one
+func stripInlineCode(line string) []string {
+	texts := make([]string, 0)
+	start := 0
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		open := backtickRun(line, i)
+		end := findBacktickRun(line, i+open, open)
+		if end < 0 {
+			// Unclosed backticks are treated as normal characters
+			i += open
+			continue
+		}
+		texts = append(texts, line[start:i])
+		i = end + open
+		start = i
+	}
+	return append(texts, line[start:])
+}
+
+// Length of backtick string at i
+func backtickRun(line string, i int) int {
+	n := 0
+	for i+n < len(line) && line[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// Find backtick string of exactly length n from i
+func findBacktickRun(line string, i, n int) int {
+	for i < len(line) {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRun(line, i)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// Find mentions in plain text
+func findMentions(text string, m Mentions) {
+	for _, idx := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		// Login continues, e.g. @alice_bot is not a mention of alice
+		if end := idx[1]; end < len(text) {
+			if c := text[end]; c == '_' || c == '-' || isAlnum(c) {
+				continue
+			}
+		}
+		login := strings.ToLower(text[idx[2]:idx[3]])
+		if idx[4] >= 0 {
+			m.Teams[login+"/"+strings.ToLower(text[idx[4]:idx[5]])] = true
+		} else {
+			m.Users[login] = true
+		}
+	}
+}
+
+func isAlnum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
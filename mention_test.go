@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func mentionList(set map[string]bool) string {
+	list := make([]string, 0, len(set))
+	for k := range set {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func TestParseMentions(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		users string
+		teams string
+	}{
+		{"plain", "@alice please review", "alice", ""},
+		{"case insensitive", "cc @Alice", "alice", ""},
+		{"punctuation", "thanks, @alice! and (@bob)", "alice,bob", ""},
+		{"longer login", "@alice-bot ran", "alice-bot", ""},
+		{"underscore", "@alice_bot ran", "", ""},
+		{"email", "mail to foo@alice.com", "", ""},
+		{"path", "see owner/repo@alice", "", ""},
+		{"team", "@myorg/backend please review", "", "myorg/backend"},
+		{"team and user", "@myorg/Backend and @alice", "alice", "myorg/backend"},
+		{"fenced code", "```\n@alice\n```\n@bob", "bob", ""},
+		{"fenced code with info", "```go\n// @alice\n```", "", ""},
+		{"tilde fence", "~~~\n@alice\n~~~", "", ""},
+		{"quote", "> @alice wrote\n@bob", "bob", ""},
+		{"indented quote", "  > @alice", "", ""},
+		{"inline code", "run `@alice` and @bob", "bob", ""},
+		{"double backtick code", "x``@alice``y", "", ""},
+		{"backtick inside code", "``a ` @alice`` @bob", "bob", ""},
+		{"unclosed backtick", "it's ` @alice", "alice", ""},
+		{"mismatched backticks", "``code` @alice", "alice", ""},
+		{"indented code", "text\n\n    @alice\n    @bob\n\n@carol", "carol", ""},
+		{"indented paragraph continuation", "text\n    @alice", "alice", ""},
+		{"tab indented code", "\t@alice", "", ""},
+		{"crlf", "@alice\r\n@bob\r\n", "alice,bob", ""},
+	}
+	for _, c := range cases {
+		m := parseMentions(c.body)
+		if users := mentionList(m.Users); users != c.users {
+			t.Errorf("%s: users = %q, expected %q", c.name, users, c.users)
+		}
+		if teams := mentionList(m.Teams); teams != c.teams {
+			t.Errorf("%s: teams = %q, expected %q", c.name, teams, c.teams)
+		}
+	}
+}
+
+func TestIsMentioned(t *testing.T) {
+	config = &Config{Name: "alice"}
+	cases := map[string]bool{
+		"@alice":           true,
+		"@ALICE":           true,
+		"@alice-bot":       false,
+		"foo@alice.com":    false,
+		"`@alice`":         false,
+		"x``@alice``y":     false,
+		"> @alice":         false,
+		"hello @bob":       false,
+		"```\n@alice\n```": false,
+	}
+	for body, expected := range cases {
+		if actual := isMentioned(body); actual != expected {
+			t.Errorf("isMentioned(%q) = %v, expected %v", body, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Merge readiness states
+const (
+	MERGE_READY      = "ready"
+	MERGE_CONFLICTED = "conflicted"
+	MERGE_NOT_READY  = "not_ready"
+)
+
+// Count approvals by the latest review of each reviewer
+// Returns false when changes are requested
+func countApprovals(reviews []Review) (int, bool) {
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		switch r.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[r.User.Login] = r.State
+		}
+	}
+	approvals := 0
+	for _, state := range latest {
+		switch state {
+		case "APPROVED":
+			approvals++
+		case "CHANGES_REQUESTED":
+			return approvals, false
+		}
+	}
+	return approvals, true
+}
+
+// Judge merge readiness of PR
+// Returns empty string when GitHub is still calculating mergeability
+func mergeState(repo string, pr PullRequest) (string, error) {
+	detail, err := fetchPullRequest(repo, pr.Number)
+	if err != nil {
+		return "", err
+	}
+	if detail.Mergeable == nil || detail.MergeableState == "unknown" {
+		return "", nil
+	}
+	if detail.MergeableState == "dirty" || !*detail.Mergeable {
+		return MERGE_CONFLICTED, nil
+	}
+	if detail.Draft {
+		return MERGE_NOT_READY, nil
+	}
+	switch detail.MergeableState {
+	case "clean", "has_hooks":
+	default:
+		// blocked, behind or unstable
+		return MERGE_NOT_READY, nil
+	}
+
+	reviews, err := fetchReviews(repo, pr)
+	if err != nil {
+		return "", err
+	}
+	required := config.RequiredApprovals
+	if required == 0 {
+		required = 1
+	}
+	if approvals, ok := countApprovals(reviews); !ok || approvals < required {
+		return MERGE_NOT_READY, nil
+	}
+
+	ci, err := fetchCIResult(repo, detail.Head.Sha)
+	if err != nil {
+		return "", err
+	}
+	if ci.State != "" && ci.State != CI_SUCCESS {
+		return MERGE_NOT_READY, nil
+	}
+	return MERGE_READY, nil
+}
+
+// Notify your PR becomes mergeable or conflicted
+func checkMergeable(repo string, pr PullRequest) {
+	if !isAuthored(pr) {
+		return
+	}
+	state, err := mergeState(repo, pr)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	if state == "" {
+		return
+	}
+
+	key := []byte(fmt.Sprintf("merge_%s_%d", repo, pr.Number))
+	v, err := db.Get(key, nil)
+	if err == nil && string(v) == state {
+		return
+	}
+	db.Put(key, []byte(state), nil)
+	// First seen, only record it
+	if err != nil {
+		return
+	}
+
+	switch state {
+	case MERGE_READY:
+		logger.Notify(fmt.Sprintf("Your PR is ready to merge: #%d %s", pr.Number, pr.Url))
+		go notify(Notification{Type: EVENT_MERGEABLE, Repo: repo, PullRequest: pr})
+	case MERGE_CONFLICTED:
+		logger.Notify(fmt.Sprintf("Your PR has conflicts: #%d %s", pr.Number, pr.Url))
+		go notify(Notification{Type: EVENT_CONFLICTED, Repo: repo, PullRequest: pr})
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notification thread of GitHub notifications API
+type NotificationThread struct {
+	Id         string              `json:"id"`
+	Reason     string              `json:"reason"`
+	UpdatedAt  string              `json:"updated_at"`
+	Repository Repository          `json:"repository"`
+	Subject    NotificationSubject `json:"subject"`
+}
+
+type NotificationSubject struct {
+	Title            string `json:"title"`
+	Url              string `json:"url"`
+	LatestCommentUrl string `json:"latest_comment_url"`
+	Type             string `json:"type"`
+}
+
+// Loop and watch notifications API
+func watchNotifications() {
+	lastModified := ""
+	for {
+		interval := pollNotifications(&lastModified)
+		time.Sleep(rateLimit.Delay(interval))
+	}
+}
+
+// Fetch notifications which are modified since last polling
+// @return time.Duration until next polling
+func pollNotifications(lastModified *string) time.Duration {
+	interval := time.Second * time.Duration(config.PollingTime)
+	logger.Passive("Watch notifications")
+
+	headers := map[string]string{}
+	if *lastModified != "" {
+		headers["If-Modified-Since"] = *lastModified
+	}
+	resp, buf, err := doRequest("GET", withPerPage(apiBase+"/notifications"), headers, nil)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return interval
+	}
+
+	// Server tells minimum polling interval
+	if v, err := strconv.Atoi(resp.Header.Get("X-Poll-Interval")); err == nil {
+		if poll := time.Second * time.Duration(v); poll > interval {
+			interval = poll
+		}
+	}
+
+	switch resp.StatusCode {
+	case 304:
+		return interval
+	case 200:
+	default:
+		logger.Error(fmt.Sprintf("[ERROR] HTTP response failed: %d, %s", resp.StatusCode, string(buf)))
+		return interval
+	}
+	if v := resp.Header.Get("Last-Modified"); v != "" {
+		*lastModified = v
+	}
+
+	var threads = make([]NotificationThread, 0)
+	if err := json.Unmarshal(buf, &threads); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return interval
+	}
+	// Follow rest of pages
+	if next := nextPageUrl(resp.Header.Get("Link")); next != "" {
+		buf, err := sendListRequest(next, nil)
+		if err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return interval
+		}
+		rest := make([]NotificationThread, 0)
+		if err := json.Unmarshal(buf, &rest); err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return interval
+		}
+		threads = append(threads, rest...)
+	}
+	for _, t := range threads {
+		if !isWatchedRepository(t.Repository.FullName) {
+			continue
+		}
+		if t.Subject.Type != "PullRequest" && t.Subject.Type != "Issue" {
+			continue
+		}
+		// Skip thread which is already handled
+		key := []byte("thread_" + t.Id)
+		if v, err := db.Get(key, nil); err == nil && string(v) == t.UpdatedAt {
+			continue
+		}
+		handleNotificationThread(t)
+		db.Put(key, []byte(t.UpdatedAt), nil)
+	}
+	logCacheStats()
+	return interval
+}
+
+// Check repository is in config
+func isWatchedRepository(repo string) bool {
+	for _, r := range watchedRepositories() {
+		if r == repo {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify notification reason and dispatch to detections
+func handleNotificationThread(t NotificationThread) {
+	repo := t.Repository.FullName
+	if t.Subject.Type == "Issue" {
+		handleIssueThread(t)
+		return
+	}
+
+	buf, err := sendRequest("GET", t.Subject.Url, nil, nil)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	var pr PullRequest
+	if err := json.Unmarshal(buf, &pr); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	switch t.Reason {
+	case "assign":
+		checkAssignee(repo, pr)
+	case "review_requested":
+		checkReviewRequests(repo, pr)
+	case "mention", "team_mention":
+		handleMentionThread(repo, pr, t)
+	case "author", "comment", "state_change":
+		if !isAuthored(pr) {
+			return
+		}
+		handleAuthoredThread(repo, pr, t)
+	case "ci_activity":
+		checkCIStatus(repo, pr)
+	}
+}
+
+// Notify activity on your PR which is referred by notification
+// Existing activities are recorded silently when the PR is seen for the first time,
+// but the activity which created this thread is notified
+func handleAuthoredThread(repo string, pr PullRequest, t NotificationThread) {
+	if pr.State == "closed" {
+		// Closing is the activity of this thread
+		trackAuthored(repo, pr)
+		checkClosedPullRequest(repo, pr.Number)
+		return
+	}
+
+	tracked := isTrackedAuthored(repo, pr)
+	checkIssueComment(repo, pr)
+	checkReviewComment(repo, pr)
+	checkReviews(repo, pr)
+	trackAuthored(repo, pr)
+	if tracked || t.Subject.LatestCommentUrl == "" || t.Subject.LatestCommentUrl == t.Subject.Url {
+		return
+	}
+
+	buf, err := sendRequest("GET", t.Subject.LatestCommentUrl, nil, nil)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	if strings.Contains(t.Subject.LatestCommentUrl, "/reviews/") {
+		var r Review
+		if err := json.Unmarshal(buf, &r); err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return
+		}
+		if isAuthoredReviewActivity(r) {
+			notifyAuthoredReview(repo, pr, r)
+		}
+		return
+	}
+	var c Comment
+	if err := json.Unmarshal(buf, &c); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	if c.User.Login != config.Name {
+		notifyAuthoredComment(repo, pr, c)
+	}
+}
+
+// Classify issue notification reason and dispatch to detections
+func handleIssueThread(t NotificationThread) {
+	repo := t.Repository.FullName
+	if _, issues := repositoryOption(repo); !issues {
+		return
+	}
+	buf, err := sendRequest("GET", t.Subject.Url, nil, nil)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	var issue Issue
+	if err := json.Unmarshal(buf, &issue); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	switch t.Reason {
+	case "assign":
+		checkIssueAssignee(repo, issue)
+	case "mention", "team_mention":
+		checkIssueBody(repo, issue)
+		checkIssueMentions(repo, issue)
+	}
+	checkIssueLabels(repo, issue)
+}
+
+// Notify mensioned comment which is referred by notification
+func handleMentionThread(repo string, pr PullRequest, t NotificationThread) {
+	// Latest comment is not a comment (e.g. PR body), check all comments
+	if t.Subject.LatestCommentUrl == "" || t.Subject.LatestCommentUrl == t.Subject.Url {
+		checkPullRequestBody(repo, pr)
+		checkIssueComment(repo, pr)
+		checkReviewComment(repo, pr)
+		checkReviews(repo, pr)
+		return
+	}
+
+	buf, err := sendRequest("GET", t.Subject.LatestCommentUrl, nil, nil)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	var c Comment
+	if err := json.Unmarshal(buf, &c); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+
+	// Review body is referred as review URL
+	if strings.Contains(t.Subject.LatestCommentUrl, "/reviews/") {
+		checkReviews(repo, pr)
+		return
+	}
+
+	if strings.Contains(t.Subject.LatestCommentUrl, "/pulls/comments/") {
+		handleReviewComments(repo, pr, []Comment{c})
+	} else {
+		handleIssueComments(repo, pr, []Comment{c})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Notification event types
+type EventType string
+
+const (
+	EVENT_ASSIGNED         EventType = "assigned"
+	EVENT_MENTIONED        EventType = "mentioned"
+	EVENT_REVIEW_REQUESTED EventType = "review_requested"
+	EVENT_AUTO_APPROVED    EventType = "auto_approved"
+
+	EVENT_TEAM_REVIEW_REQUESTED EventType = "team_review_requested"
+
+	EVENT_ISSUE_ASSIGNED  EventType = "issue_assigned"
+	EVENT_ISSUE_MENTIONED EventType = "issue_mentioned"
+	EVENT_ISSUE_LABELED   EventType = "issue_labeled"
+
+	EVENT_REVIEW_SUBMITTED EventType = "review_submitted"
+	EVENT_COMMENTED        EventType = "commented"
+	EVENT_MERGED           EventType = "merged"
+	EVENT_CLOSED           EventType = "closed"
+	EVENT_CI_STATUS        EventType = "ci_status"
+	EVENT_MERGEABLE        EventType = "mergeable"
+	EVENT_CONFLICTED       EventType = "conflicted"
+	EVENT_NEW_COMMITS      EventType = "new_commits"
+)
+
+// Notification event which is sent to all notifiers
+// PullRequest holds the issue on issue events
+type Notification struct {
+	Type        EventType
+	Repo        string
+	PullRequest PullRequest
+	CommentUrl  string
+	Team        string
+	Label       string
+	Actor       string
+	State       string
+	Check       string
+	Repeat      bool
+	Escalated   bool
+}
+
+// Notification title
+// Prefixed on escalation after SLA breach
+func (n Notification) Title() string {
+	if n.Escalated {
+		return "[ESCALATED] " + n.title()
+	}
+	return n.title()
+}
+
+func (n Notification) title() string {
+	switch n.Type {
+	case EVENT_ASSIGNED:
+		return fmt.Sprintf("New Pull Request Assigned: #%d", n.PullRequest.Number)
+	case EVENT_MENTIONED:
+		return fmt.Sprintf("Mensioned in PR: #%d", n.PullRequest.Number)
+	case EVENT_REVIEW_REQUESTED:
+		return fmt.Sprintf("You added reviewer: #%d", n.PullRequest.Number)
+	case EVENT_TEAM_REVIEW_REQUESTED:
+		return fmt.Sprintf("Team @%s added reviewer: #%d", n.Team, n.PullRequest.Number)
+	case EVENT_ISSUE_ASSIGNED:
+		return fmt.Sprintf("New Issue Assigned: #%d", n.PullRequest.Number)
+	case EVENT_ISSUE_MENTIONED:
+		return fmt.Sprintf("Mensioned in issue: #%d", n.PullRequest.Number)
+	case EVENT_ISSUE_LABELED:
+		return fmt.Sprintf("Issue labeled %s: #%d", n.Label, n.PullRequest.Number)
+	case EVENT_REVIEW_SUBMITTED:
+		switch n.State {
+		case "APPROVED":
+			return fmt.Sprintf("%s approved your PR: #%d", n.Actor, n.PullRequest.Number)
+		case "CHANGES_REQUESTED":
+			return fmt.Sprintf("%s requested changes: #%d", n.Actor, n.PullRequest.Number)
+		}
+		return fmt.Sprintf("%s reviewed your PR: #%d", n.Actor, n.PullRequest.Number)
+	case EVENT_COMMENTED:
+		return fmt.Sprintf("%s commented on your PR: #%d", n.Actor, n.PullRequest.Number)
+	case EVENT_MERGED:
+		return fmt.Sprintf("%s merged your PR: #%d", n.Actor, n.PullRequest.Number)
+	case EVENT_CLOSED:
+		return fmt.Sprintf("%s closed your PR: #%d", n.Actor, n.PullRequest.Number)
+	case EVENT_CI_STATUS:
+		if n.Check != "" {
+			return fmt.Sprintf("CI %s (%s): #%d", n.State, n.Check, n.PullRequest.Number)
+		}
+		return fmt.Sprintf("CI %s: #%d", n.State, n.PullRequest.Number)
+	case EVENT_MERGEABLE:
+		return fmt.Sprintf("Your PR is ready to merge: #%d", n.PullRequest.Number)
+	case EVENT_CONFLICTED:
+		return fmt.Sprintf("Your PR has conflicts: #%d", n.PullRequest.Number)
+	case EVENT_NEW_COMMITS:
+		return fmt.Sprintf("New commits since your review: #%d", n.PullRequest.Number)
+	case EVENT_AUTO_APPROVED:
+		return fmt.Sprintf("PR has approved automatically: #%d", n.PullRequest.Number)
+	}
+	return fmt.Sprintf("Pull Request: #%d", n.PullRequest.Number)
+}
+
+// Notification subtitle
+func (n Notification) Subtitle() string {
+	return n.PullRequest.Title
+}
+
+// URL to open when notification is clicked
+func (n Notification) Url() string {
+	if n.CommentUrl != "" {
+		return n.CommentUrl
+	}
+	return n.PullRequest.Url
+}
+
+// Notifier sends notification to the specific channel
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Notifier names
+const (
+	NOTIFIER_TERMINAL    = "terminal-notifier"
+	NOTIFIER_FREEDESKTOP = "freedesktop"
+	NOTIFIER_SLACK       = "slack"
+	NOTIFIER_WEBHOOK     = "webhook"
+	NOTIFIER_EMAIL       = "email"
+)
+
+// Enabled notifiers
+var notifiers = make(map[string]Notifier)
+
+// Create notifier from name
+func newNotifier(name string) (Notifier, error) {
+	switch name {
+	case NOTIFIER_TERMINAL:
+		return newTerminalNotifier(), nil
+	case NOTIFIER_FREEDESKTOP:
+		return newFreedesktopNotifier(config.Freedesktop)
+	case NOTIFIER_SLACK:
+		return newSlackNotifier(config.Slack)
+	case NOTIFIER_WEBHOOK:
+		return newWebhookNotifier(config.Webhook)
+	case NOTIFIER_EMAIL:
+		return newEmailNotifier(config.Email)
+	}
+	return nil, fmt.Errorf("Unknown notifier: %s", name)
+}
+
+// Default notifier for running platform
+func defaultNotifier() string {
+	if runtime.GOOS == "linux" {
+		return NOTIFIER_FREEDESKTOP
+	}
+	return NOTIFIER_TERMINAL
+}
+
+// Setup notifiers which are specified in config
+func setupNotifiers() error {
+	names := config.Notifiers
+	if len(names) == 0 {
+		names = []string{defaultNotifier()}
+	}
+	for _, name := range names {
+		n, err := newNotifier(name)
+		if err != nil {
+			return err
+		}
+		notifiers[name] = n
+	}
+	return nil
+}
+
+// Send notification to all enabled notifiers
+func notify(n Notification) {
+	if *isSilent {
+		return
+	}
+	notifyAll(notifiers, n)
+}
+
+// Send notification to notifiers concurrently
+// A slow backend (e.g. webhook retry) doesn't delay others
+func notifyAll(targets map[string]Notifier, n Notification) {
+	var wg sync.WaitGroup
+	for name, nt := range targets {
+		wg.Add(1)
+		go func(name string, nt Notifier) {
+			defer wg.Done()
+			if err := nt.Notify(n); err != nil {
+				logger.Error(fmt.Sprintf("[ERROR] %s: %s", name, err.Error()))
+			}
+		}(name, nt)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Email notifier configuration
+type EmailConfig struct {
+	Host           string   `toml:"host"`
+	Port           int      `toml:"port"`
+	Username       string   `toml:"username"`
+	Password       string   `toml:"password"`
+	StartTLS       bool     `toml:"starttls"`
+	From           string   `toml:"from"`
+	To             []string `toml:"to"`
+	Mode           string   `toml:"mode"`
+	DigestInterval int      `toml:"digest_interval"`
+}
+
+// Email sending modes
+const (
+	EMAIL_MODE_EVENT  = "event"
+	EMAIL_MODE_DIGEST = "digest"
+)
+
+var emailTextTemplate = template.Must(template.New("text").Parse(`{{range .}}{{.Title}}
+  {{.Repo}} #{{.PullRequest.Number}} {{.PullRequest.Title}}
+  {{.Url}}
+
+{{end}}--
+github-assignee-notifier
+`))
+
+var emailHtmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<html>
+<body>
+<ul>
+{{range .}}<li>
+  <strong>{{.Title}}</strong><br>
+  {{.Repo}} #{{.PullRequest.Number}} <a href="{{.Url}}">{{.PullRequest.Title}}</a>
+</li>
+{{end}}</ul>
+<p>-- github-assignee-notifier</p>
+</body>
+</html>
+`))
+
+// Notifier which sends email via SMTP
+type EmailNotifier struct {
+	config EmailConfig
+	mu     sync.Mutex
+	queue  []Notification
+}
+
+func newEmailNotifier(c EmailConfig) (*EmailNotifier, error) {
+	if c.Host == "" {
+		return nil, fmt.Errorf("Email host is empty")
+	}
+	if c.From == "" || len(c.To) == 0 {
+		return nil, fmt.Errorf("Email from and to are required")
+	}
+	if c.Port == 0 {
+		c.Port = 587
+	}
+	if c.Mode == "" {
+		c.Mode = EMAIL_MODE_EVENT
+	}
+	if c.DigestInterval == 0 {
+		c.DigestInterval = 24 * 60 * 60
+	}
+
+	e := &EmailNotifier{
+		config: c,
+		queue:  make([]Notification, 0),
+	}
+	switch c.Mode {
+	case EMAIL_MODE_EVENT:
+	case EMAIL_MODE_DIGEST:
+		go e.runDigest()
+	default:
+		return nil, fmt.Errorf("Unknown email mode: %s", c.Mode)
+	}
+	return e, nil
+}
+
+// Send notification, or queue it for digest
+func (e *EmailNotifier) Notify(n Notification) error {
+	if e.config.Mode == EMAIL_MODE_DIGEST {
+		e.mu.Lock()
+		e.queue = append(e.queue, n)
+		e.mu.Unlock()
+		return nil
+	}
+	return e.send(n.Title(), []Notification{n})
+}
+
+// Send queued notifications periodically
+func (e *EmailNotifier) runDigest() {
+	ticker := time.NewTicker(time.Second * time.Duration(e.config.DigestInterval))
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.flush(); err != nil {
+				logger.Error("[ERROR] email: " + err.Error())
+			}
+		}
+	}
+}
+
+// Send digest email of queued notifications
+func (e *EmailNotifier) flush() error {
+	e.mu.Lock()
+	list := e.queue
+	e.queue = make([]Notification, 0)
+	e.mu.Unlock()
+
+	if len(list) == 0 {
+		return nil
+	}
+	subject := fmt.Sprintf("GitHub digest: %d notifications", len(list))
+	return e.send(subject, list)
+}
+
+// Build multipart message which contains plaintext and HTML
+func (e *EmailNotifier) message(subject string, list []Notification) ([]byte, error) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+
+	text, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=UTF-8"}})
+	if err != nil {
+		return nil, err
+	}
+	if err := emailTextTemplate.Execute(text, list); err != nil {
+		return nil, err
+	}
+	html, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=UTF-8"}})
+	if err != nil {
+		return nil, err
+	}
+	if err := emailHtmlTemplate.Execute(html, list); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	msg := new(bytes.Buffer)
+	fmt.Fprintf(msg, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// Send email via SMTP
+func (e *EmailNotifier) send(subject string, list []Notification) error {
+	msg, err := e.message(subject, list)
+	if err != nil {
+		return err
+	}
+
+	c, err := smtp.Dial(net.JoinHostPort(e.config.Host, strconv.Itoa(e.config.Port)))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if e.config.StartTLS {
+		if err := c.StartTLS(&tls.Config{ServerName: e.config.Host}); err != nil {
+			return err
+		}
+	}
+	if e.config.Username != "" {
+		auth := smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.config.From); err != nil {
+		return err
+	}
+	for _, to := range e.config.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
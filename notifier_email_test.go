@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"mime"
+	"net/mail"
+	"testing"
+)
+
+func TestEmailMessageSubject(t *testing.T) {
+	e, err := newEmailNotifier(EmailConfig{Host: "localhost", From: "from@example.com", To: []string{"to@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := "Issue labeled 優先: #42"
+	buf, err := e.message(subject, []Notification{{Type: EVENT_ISSUE_LABELED, Label: "優先"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := msg.Header.Get("Subject")
+	for _, c := range []byte(raw) {
+		if c >= 0x80 {
+			t.Fatalf("Subject header must be ASCII: %q", raw)
+		}
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != subject {
+		t.Errorf("Decoded subject %q, expected %q", decoded, subject)
+	}
+}
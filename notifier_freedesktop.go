@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Freedesktop notifier configuration
+type FreedesktopConfig struct {
+	Timeout int               `toml:"timeout"`
+	Urgency map[string]string `toml:"urgency"`
+}
+
+// Default urgency levels for each event
+var defaultUrgencies = map[EventType]string{
+	EVENT_ASSIGNED:              "critical",
+	EVENT_MENTIONED:             "normal",
+	EVENT_REVIEW_REQUESTED:      "normal",
+	EVENT_TEAM_REVIEW_REQUESTED: "normal",
+	EVENT_AUTO_APPROVED:         "low",
+	EVENT_ISSUE_ASSIGNED:        "critical",
+	EVENT_ISSUE_MENTIONED:       "normal",
+	EVENT_ISSUE_LABELED:         "normal",
+	EVENT_REVIEW_SUBMITTED:      "normal",
+	EVENT_COMMENTED:             "low",
+	EVENT_MERGED:                "normal",
+	EVENT_CLOSED:                "normal",
+	EVENT_CI_STATUS:             "normal",
+	EVENT_MERGEABLE:             "normal",
+	EVENT_CONFLICTED:            "critical",
+	EVENT_NEW_COMMITS:           "normal",
+}
+
+// Notifier for Linux desktop using notify-send command which follows freedesktop notifications spec
+type FreedesktopNotifier struct {
+	icon    string
+	timeout int
+	urgency map[EventType]string
+}
+
+func newFreedesktopNotifier(c FreedesktopConfig) (*FreedesktopNotifier, error) {
+	f := &FreedesktopNotifier{
+		icon:    filepath.Join(baseDir, "icon.png"),
+		timeout: 300,
+		urgency: make(map[EventType]string),
+	}
+	if c.Timeout > 0 {
+		f.timeout = c.Timeout
+	}
+	for e, u := range defaultUrgencies {
+		f.urgency[e] = u
+	}
+	for e, u := range c.Urgency {
+		switch u {
+		case "low", "normal", "critical":
+			f.urgency[EventType(e)] = u
+		default:
+			return nil, fmt.Errorf("Invalid urgency level for %s: %s", e, u)
+		}
+	}
+	return f, nil
+}
+
+// Send notification
+func (f *FreedesktopNotifier) Notify(n Notification) error {
+	urgency, ok := f.urgency[n.Type]
+	if !ok {
+		urgency = "normal"
+	}
+	body := n.Url()
+	if subtitle := n.Subtitle(); subtitle != "" {
+		body = subtitle + "\n" + body
+	}
+	args := []string{
+		"--app-name=github-assignee-notifier",
+		"--icon=" + f.icon,
+		"--urgency=" + urgency,
+		fmt.Sprintf("--expire-time=%d", f.timeout*1000),
+	}
+
+	// Wait for the default action (click on the popup) in background and open the URL
+	// Popup may stay until it's closed, e.g. critical urgency
+	var out bytes.Buffer
+	cmd := exec.Command("notify-send", append(args, "--action=default=Open", "--wait", n.Title(), body)...)
+	cmd.Stdout = &out
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			// Older notify-send doesn't support actions, send plain notification
+			if err := exec.Command("notify-send", append(args, n.Title(), body)...).Run(); err != nil {
+				logger.Error("[ERROR] freedesktop: " + err.Error())
+			}
+			return
+		}
+		if strings.TrimSpace(out.String()) == "default" {
+			if err := exec.Command("xdg-open", n.Url()).Run(); err != nil {
+				logger.Error("[ERROR] freedesktop: " + err.Error())
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Escape control characters of Slack mrkdwn
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Slack notifier configuration
+type SlackConfig struct {
+	WebhookUrl string   `toml:"webhook_url"`
+	Channel    string   `toml:"channel"`
+	Username   string   `toml:"username"`
+	Events     []string `toml:"events"`
+}
+
+// Notifier which posts message to Slack incoming webhook
+type SlackNotifier struct {
+	url      string
+	channel  string
+	username string
+	events   map[EventType]bool
+	client   *http.Client
+}
+
+func newSlackNotifier(c SlackConfig) (*SlackNotifier, error) {
+	if c.WebhookUrl == "" {
+		return nil, fmt.Errorf("Slack webhook_url is empty")
+	}
+	s := &SlackNotifier{
+		url:      c.WebhookUrl,
+		channel:  c.Channel,
+		username: c.Username,
+		events:   make(map[EventType]bool),
+		client:   httpClient,
+	}
+	for _, e := range c.Events {
+		s.events[EventType(e)] = true
+	}
+	return s, nil
+}
+
+// Build Block Kit message payload
+func (s *SlackNotifier) message(n Notification) map[string]interface{} {
+	pr := n.PullRequest
+	title := n.Title()
+	if n.Repeat {
+		title = "[REPEAT] " + title
+	}
+
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{
+				"type": "plain_text",
+				"text": title,
+			},
+		},
+		map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*<%s|%s>*", pr.Url, slackEscaper.Replace(pr.Title)),
+			},
+			"fields": []interface{}{
+				map[string]interface{}{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Repository*\n%s", n.Repo),
+				},
+				map[string]interface{}{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Pull Request*\n#%d", pr.Number),
+				},
+				map[string]interface{}{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Author*\n%s", pr.User.Login),
+				},
+			},
+		},
+		map[string]interface{}{
+			"type": "actions",
+			"elements": []interface{}{
+				map[string]interface{}{
+					"type": "button",
+					"text": map[string]interface{}{
+						"type": "plain_text",
+						"text": "Open",
+					},
+					"url": n.Url(),
+				},
+			},
+		},
+	}
+
+	msg := map[string]interface{}{
+		"text":   fmt.Sprintf("%s %s %s", slackEscaper.Replace(title), slackEscaper.Replace(pr.Title), n.Url()),
+		"blocks": blocks,
+	}
+	if s.channel != "" {
+		msg["channel"] = s.channel
+	}
+	if s.username != "" {
+		msg["username"] = s.username
+	}
+	return msg
+}
+
+// Send notification
+func (s *SlackNotifier) Notify(n Notification) error {
+	if len(s.events) > 0 && !s.events[n.Type] {
+		return nil
+	}
+	b, err := json.Marshal(s.message(n))
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	buf, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Slack webhook failed: %d, %s", resp.StatusCode, string(buf))
+	}
+	return nil
+}
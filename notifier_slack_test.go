@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestSlackServer(t *testing.T, status int, received *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// t.Fatal can't be called out of the test goroutine
+		buf, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			w.WriteHeader(400)
+			return
+		}
+		msg := make(map[string]interface{})
+		if err := json.Unmarshal(buf, &msg); err != nil {
+			t.Error(err)
+			w.WriteHeader(400)
+			return
+		}
+		*received = append(*received, msg)
+		w.WriteHeader(status)
+	}))
+}
+
+func TestSlackNotifierNotify(t *testing.T) {
+	received := make([]map[string]interface{}, 0)
+	server := newTestSlackServer(t, 200, &received)
+	defer server.Close()
+
+	s, err := newSlackNotifier(SlackConfig{WebhookUrl: server.URL, Channel: "#review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := Notification{
+		Type: EVENT_ASSIGNED,
+		Repo: "owner/repo",
+		PullRequest: PullRequest{
+			Number: 42,
+			Title:  "Compare a < b && b > c | d",
+			Url:    "https://github.com/owner/repo/pull/42",
+		},
+	}
+	if err := s.Notify(n); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(received))
+	}
+
+	msg := received[0]
+	if msg["channel"] != "#review" {
+		t.Errorf("Unexpected channel: %v", msg["channel"])
+	}
+	blocks := msg["blocks"].([]interface{})
+	section := blocks[1].(map[string]interface{})["text"].(map[string]interface{})
+	expected := "*<https://github.com/owner/repo/pull/42|Compare a &lt; b &amp;&amp; b &gt; c | d>*"
+	if section["text"] != expected {
+		t.Errorf("Unexpected section text: %v", section["text"])
+	}
+}
+
+func TestSlackNotifierEvents(t *testing.T) {
+	received := make([]map[string]interface{}, 0)
+	server := newTestSlackServer(t, 200, &received)
+	defer server.Close()
+
+	s, err := newSlackNotifier(SlackConfig{WebhookUrl: server.URL, Events: []string{string(EVENT_MENTIONED)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Notify(Notification{Type: EVENT_ASSIGNED}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Notify(Notification{Type: EVENT_MENTIONED}); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("Expected only mentioned event is sent, got %d messages", len(received))
+	}
+}
+
+func TestSlackNotifierError(t *testing.T) {
+	received := make([]map[string]interface{}, 0)
+	server := newTestSlackServer(t, 500, &received)
+	defer server.Close()
+
+	s, err := newSlackNotifier(SlackConfig{WebhookUrl: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Notify(Notification{Type: EVENT_ASSIGNED}); err == nil {
+		t.Error("Expected error on 500 response")
+	}
+}
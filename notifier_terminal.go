@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+)
+
+// Notifier for macOS using terminal-notifier command
+type TerminalNotifier struct {
+	icon string
+}
+
+func newTerminalNotifier() *TerminalNotifier {
+	return &TerminalNotifier{
+		icon: filepath.Join(baseDir, "icon.png"),
+	}
+}
+
+// Send notification
+func (t *TerminalNotifier) Notify(n Notification) error {
+	args := []string{
+		"-title",
+		n.Title(),
+	}
+	if subtitle := n.Subtitle(); subtitle != "" {
+		args = append(args, "-subtitle", subtitle)
+	}
+	args = append(args,
+		"-timeout",
+		"300",
+		"-open",
+		n.Url(),
+		"-message",
+		n.Url(),
+		"-appIcon",
+		t.icon,
+	)
+
+	return exec.Command("terminal-notifier", args...).Run()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Webhook notifier configuration
+type WebhookConfig struct {
+	Url     string `toml:"url"`
+	Secret  string `toml:"secret"`
+	Retry   int    `toml:"retry"`
+	Backoff int    `toml:"backoff"`
+}
+
+// JSON payload which is sent to the webhook
+type WebhookPayload struct {
+	Type        EventType          `json:"type"`
+	Repeat      bool               `json:"repeat"`
+	Escalated   bool               `json:"escalated"`
+	Repository  string             `json:"repository"`
+	PullRequest WebhookPullRequest `json:"pull_request"`
+	CommentUrl  string             `json:"comment_url,omitempty"`
+	Team        string             `json:"team,omitempty"`
+	Label       string             `json:"label,omitempty"`
+	Actor       string             `json:"actor,omitempty"`
+	State       string             `json:"state,omitempty"`
+	Check       string             `json:"check,omitempty"`
+	Timestamp   string             `json:"timestamp"`
+}
+
+// Pull request fields in webhook payload
+type WebhookPullRequest struct {
+	Id        int      `json:"id"`
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	Url       string   `json:"html_url"`
+	User      string   `json:"user"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Assignees []string `json:"assignees"`
+}
+
+// Notifier which posts JSON event to arbitrary URL
+type WebhookNotifier struct {
+	url     string
+	secret  []byte
+	retry   int
+	backoff time.Duration
+	client  *http.Client
+}
+
+func newWebhookNotifier(c WebhookConfig) (*WebhookNotifier, error) {
+	if c.Url == "" {
+		return nil, fmt.Errorf("Webhook url is empty")
+	}
+	w := &WebhookNotifier{
+		url:     c.Url,
+		secret:  []byte(c.Secret),
+		retry:   3,
+		backoff: time.Second,
+		client:  httpClient,
+	}
+	if c.Retry > 0 {
+		w.retry = c.Retry
+	}
+	if c.Backoff > 0 {
+		w.backoff = time.Duration(c.Backoff) * time.Second
+	}
+	return w, nil
+}
+
+// Build payload from notification
+func (w *WebhookNotifier) payload(n Notification) WebhookPayload {
+	pr := n.PullRequest
+	assignees := make([]string, 0)
+	for _, a := range pr.Assignees {
+		assignees = append(assignees, a.Login)
+	}
+	assignee := ""
+	if len(assignees) > 0 {
+		assignee = assignees[0]
+	}
+	return WebhookPayload{
+		Type:       n.Type,
+		Repeat:     n.Repeat,
+		Escalated:  n.Escalated,
+		Repository: n.Repo,
+		PullRequest: WebhookPullRequest{
+			Id:        pr.Id,
+			Number:    pr.Number,
+			Title:     pr.Title,
+			Url:       pr.Url,
+			User:      pr.User.Login,
+			Assignee:  assignee,
+			Assignees: assignees,
+		},
+		CommentUrl: n.CommentUrl,
+		Team:       n.Team,
+		Label:      n.Label,
+		Actor:      n.Actor,
+		State:      n.State,
+		Check:      n.Check,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Calculate HMAC-SHA256 signature of body
+func (w *WebhookNotifier) sign(body []byte) string {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send notification, retry with exponential backoff on failure
+func (w *WebhookNotifier) Notify(n Notification) error {
+	body, err := json.Marshal(w.payload(n))
+	if err != nil {
+		return err
+	}
+
+	wait := w.backoff
+	for i := 0; ; i++ {
+		retryable, err := w.send(n.Type, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || i >= w.retry {
+			return err
+		}
+		logger.Warn(fmt.Sprintf("[WARN] Webhook failed, retry after %s: %s", wait, err.Error()))
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// Send request once. Returns whether error is retryable
+func (w *WebhookNotifier) send(event EventType, body []byte) (bool, error) {
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("User-Agent", "github-assignee-notifier")
+	req.Header.Add("X-Notifier-Event", string(event))
+	if len(w.secret) > 0 {
+		req.Header.Add("X-Notifier-Signature-256", w.sign(body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	buf, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("Webhook failed: %d, %s", resp.StatusCode, string(buf))
+	return resp.StatusCode >= 500 || resp.StatusCode == 429, err
+}
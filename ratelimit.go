@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Slow down polling when remaining rate is lower than this ratio
+const RATE_LIMIT_SLOWDOWN_RATIO = 0.2
+
+// Stop polling until reset when remaining rate is lower than this ratio of the limit
+const RATE_LIMIT_RESERVE_RATIO = 0.01
+
+// Default wait for secondary rate limit without Retry-After
+const SECONDARY_RATE_LIMIT_WAIT = 60
+
+// Rate limit resources which have their own budget
+// Response tells it by X-RateLimit-Resource header
+const (
+	RATE_LIMIT_CORE    = "core"
+	RATE_LIMIT_SEARCH  = "search"
+	RATE_LIMIT_GRAPHQL = "graphql"
+)
+
+// Budget of a rate limit resource
+type RateBudget struct {
+	limit       int
+	remaining   int
+	reset       time.Time
+	pausedUntil time.Time
+}
+
+// Remaining rate which is kept for other processes
+func (b *RateBudget) reserve() int {
+	return int(float64(b.limit) * RATE_LIMIT_RESERVE_RATIO)
+}
+
+// GitHub API rate limit state which is updated by every response
+type RateLimit struct {
+	mu      sync.Mutex
+	budgets map[string]*RateBudget
+	// Secondary rate limit applies to all resources
+	pausedUntil time.Time
+}
+
+var rateLimit = newRateLimit()
+
+func newRateLimit() *RateLimit {
+	return &RateLimit{budgets: make(map[string]*RateBudget)}
+}
+
+// Error for rate limited response
+type RateLimitError struct {
+	Until time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("API rate limit exceeded, paused until %s", e.Until.Format("15:04:05"))
+}
+
+// Guess rate limit resource from request URL
+func rateLimitResource(url string) string {
+	switch {
+	case strings.Contains(url, "/search/"):
+		return RATE_LIMIT_SEARCH
+	case strings.HasSuffix(url, "/graphql"):
+		return RATE_LIMIT_GRAPHQL
+	}
+	return RATE_LIMIT_CORE
+}
+
+// Find budget of resource, must be called with lock
+func (r *RateLimit) budget(resource string) *RateBudget {
+	b, ok := r.budgets[resource]
+	if !ok {
+		b = &RateBudget{limit: GITHUB_API_LIMIT, remaining: GITHUB_API_LIMIT}
+		r.budgets[resource] = b
+	}
+	return b
+}
+
+// Update state from response headers
+// Returns error when the response is rate limited
+func (r *RateLimit) Update(resource string, resp *http.Response) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := resp.Header.Get("X-RateLimit-Resource"); v != "" {
+		resource = v
+	}
+	b := r.budget(resource)
+	if v, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		b.limit = v
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		b.remaining = v
+	}
+	if v, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		b.reset = time.Unix(v, 0)
+	}
+
+	if resp.StatusCode != 403 && resp.StatusCode != 429 {
+		return nil
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		// Secondary rate limit
+		r.pausedUntil = time.Now().Add(time.Second * time.Duration(v))
+		return &RateLimitError{Until: r.pausedUntil}
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		// Primary rate limit
+		b.pausedUntil = b.reset
+		return &RateLimitError{Until: b.pausedUntil}
+	} else if resp.StatusCode == 429 {
+		r.pausedUntil = time.Now().Add(time.Second * SECONDARY_RATE_LIMIT_WAIT)
+		return &RateLimitError{Until: r.pausedUntil}
+	}
+	// Forbidden by other reasons
+	return nil
+}
+
+// Time until requests to the resource should wait
+func (r *RateLimit) waitUntil(resource string) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := r.budget(resource)
+	until := r.pausedUntil
+	if b.pausedUntil.After(until) {
+		until = b.pausedUntil
+	}
+	if b.remaining <= b.reserve() && b.reset.After(until) {
+		until = b.reset
+	}
+	return until
+}
+
+// Block while rate limited
+func (r *RateLimit) Wait(resource string) {
+	until := r.waitUntil(resource)
+	if d := time.Until(until); d > 0 {
+		logger.Warn(fmt.Sprintf("[WARN] Rate limit of %s is low, polling paused until %s", resource, until.Format("15:04:05")))
+		time.Sleep(d)
+	}
+}
+
+// Calculate wait duration until next polling
+// Stretch base duration as remaining core rate decreases
+func (r *RateLimit) Delay(base time.Duration) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := r.budget(RATE_LIMIT_CORE)
+	if b.limit == 0 {
+		return base
+	}
+	// Polling is paused by Wait() in this case
+	if b.remaining <= b.reserve() {
+		return base
+	}
+	ratio := float64(b.remaining) / float64(b.limit)
+	if ratio >= RATE_LIMIT_SLOWDOWN_RATIO {
+		return base
+	}
+
+	delay := time.Duration(float64(base) * RATE_LIMIT_SLOWDOWN_RATIO / ratio)
+	if untilReset := time.Until(b.reset); untilReset > base && delay > untilReset {
+		delay = untilReset
+	}
+	if delay > base {
+		logger.Warn(fmt.Sprintf("[WARN] %d/%d API rate remains, slow down polling to %s", b.remaining, b.limit, delay))
+	}
+	return delay
+}
+
+// Poll repositories in turn with adaptive interval
+func runScheduler(watch func(repo string)) {
+	base := time.Second * time.Duration(config.PollingTime)
+	for {
+		start := time.Now()
+		for _, repo := range watchedRepositories() {
+			watch(repo)
+		}
+
+		delay := rateLimit.Delay(base) - time.Since(start)
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+	}
+}
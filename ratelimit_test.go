@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newRateLimitResponse(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestRateLimitResource(t *testing.T) {
+	cases := map[string]string{
+		"https://api.github.com/repos/o/r/pulls":          RATE_LIMIT_CORE,
+		"https://api.github.com/search/issues?q=is%3Apr":  RATE_LIMIT_SEARCH,
+		"https://api.github.com/graphql":                  RATE_LIMIT_GRAPHQL,
+		"https://github.example.com/api/graphql":          RATE_LIMIT_GRAPHQL,
+		"https://github.example.com/api/v3/notifications": RATE_LIMIT_CORE,
+	}
+	for url, expected := range cases {
+		if actual := rateLimitResource(url); actual != expected {
+			t.Errorf("rateLimitResource(%q) = %q, expected %q", url, actual, expected)
+		}
+	}
+}
+
+func TestRateLimitSearchDoesNotBlockCore(t *testing.T) {
+	r := newRateLimit()
+	reset := time.Now().Add(time.Minute)
+	r.Update(RATE_LIMIT_SEARCH, newRateLimitResponse(200, map[string]string{
+		"X-RateLimit-Resource":  "search",
+		"X-RateLimit-Limit":     "30",
+		"X-RateLimit-Remaining": "27",
+		"X-RateLimit-Reset":     fmt.Sprint(reset.Unix()),
+	}))
+
+	if until := r.waitUntil(RATE_LIMIT_CORE); until.After(time.Now()) {
+		t.Errorf("Core requests must not wait for search budget, until %s", until)
+	}
+	if until := r.waitUntil(RATE_LIMIT_SEARCH); until.After(time.Now()) {
+		t.Errorf("Search requests must not wait with remaining budget, until %s", until)
+	}
+}
+
+func TestRateLimitExhausted(t *testing.T) {
+	r := newRateLimit()
+	reset := time.Now().Add(time.Minute)
+	err := r.Update(RATE_LIMIT_SEARCH, newRateLimitResponse(403, map[string]string{
+		"X-RateLimit-Resource":  "search",
+		"X-RateLimit-Limit":     "30",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     fmt.Sprint(reset.Unix()),
+	}))
+	if _, ok := err.(*RateLimitError); !ok {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if until := r.waitUntil(RATE_LIMIT_SEARCH); until.Unix() != reset.Unix() {
+		t.Errorf("Search requests must wait until reset, until %s", until)
+	}
+	if until := r.waitUntil(RATE_LIMIT_CORE); until.After(time.Now()) {
+		t.Errorf("Core requests must not wait, until %s", until)
+	}
+
+	// Core budget is almost exhausted
+	r.Update(RATE_LIMIT_CORE, newRateLimitResponse(200, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "40",
+		"X-RateLimit-Reset":     fmt.Sprint(reset.Unix()),
+	}))
+	if until := r.waitUntil(RATE_LIMIT_CORE); until.Unix() != reset.Unix() {
+		t.Errorf("Core requests must wait until reset, until %s", until)
+	}
+}
+
+func TestRateLimitSecondary(t *testing.T) {
+	r := newRateLimit()
+	err := r.Update(RATE_LIMIT_CORE, newRateLimitResponse(403, map[string]string{
+		"Retry-After": "30",
+	}))
+	if _, ok := err.(*RateLimitError); !ok {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	for _, resource := range []string{RATE_LIMIT_CORE, RATE_LIMIT_SEARCH, RATE_LIMIT_GRAPHQL} {
+		if until := r.waitUntil(resource); !until.After(time.Now()) {
+			t.Errorf("Secondary rate limit must pause %s", resource)
+		}
+	}
+}
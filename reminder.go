@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reminder configuration
+type ReminderConfig struct {
+	// Delays since the first notification, e.g. ["1h", "4h", "1d"]
+	Schedule []string `toml:"schedule"`
+	// Escalate when you don't act within this duration
+	Sla string `toml:"sla"`
+	// Notifiers for escalation, all enabled notifiers when empty
+	Escalate []string `toml:"escalate"`
+}
+
+// Parsed reminder policy
+var reminderSchedule []time.Duration
+var reminderSla time.Duration
+var escalationNotifiers = make(map[string]Notifier)
+
+const (
+	REMINDER_ESCALATED = 1 << iota
+	REMINDER_STOPPED
+)
+
+// Reminder state of assigned PR or issue
+// Stored as little endian uint64s of first, last, count and flags
+// 8 bytes value of the last notified timestamp is accepted for compatibility
+type Reminder struct {
+	First uint64
+	Last  uint64
+	Count uint64
+	Flags uint64
+}
+
+func decodeReminder(v []byte) Reminder {
+	if len(v) < 32 {
+		last := binary.LittleEndian.Uint64(v)
+		return Reminder{First: last, Last: last}
+	}
+	return Reminder{
+		First: binary.LittleEndian.Uint64(v[0:8]),
+		Last:  binary.LittleEndian.Uint64(v[8:16]),
+		Count: binary.LittleEndian.Uint64(v[16:24]),
+		Flags: binary.LittleEndian.Uint64(v[24:32]),
+	}
+}
+
+func (r Reminder) encode() []byte {
+	v := make([]byte, 32)
+	binary.LittleEndian.PutUint64(v[0:8], r.First)
+	binary.LittleEndian.PutUint64(v[8:16], r.Last)
+	binary.LittleEndian.PutUint64(v[16:24], r.Count)
+	binary.LittleEndian.PutUint64(v[24:32], r.Flags)
+	return v
+}
+
+func newReminder(now uint64) Reminder {
+	return Reminder{First: now, Last: now}
+}
+
+// Check reminder is due, and advance it
+// Reminders follow the schedule, or `repeat` seconds when it's empty
+func (r *Reminder) remind(now uint64) bool {
+	if r.Flags&REMINDER_STOPPED != 0 {
+		return false
+	}
+	if len(reminderSchedule) == 0 {
+		if r.Last+config.Repeat < now {
+			r.Last = now
+			r.Count++
+			return true
+		}
+		return false
+	}
+
+	// Skip passed entries, e.g. after sleep
+	due := r.Count
+	for due < uint64(len(reminderSchedule)) && r.First+uint64(reminderSchedule[due].Seconds()) <= now {
+		due++
+	}
+	if due == r.Count {
+		return false
+	}
+	r.Last = now
+	r.Count = due
+	return true
+}
+
+// Check SLA is breached, and mark it escalated
+func (r *Reminder) escalate(now uint64) bool {
+	if reminderSla == 0 || r.Flags&(REMINDER_STOPPED|REMINDER_ESCALATED) != 0 {
+		return false
+	}
+	if r.First+uint64(reminderSla.Seconds()) > now {
+		return false
+	}
+	r.Flags |= REMINDER_ESCALATED
+	r.Last = now
+	return true
+}
+
+// Parse duration which accepts days, e.g. "1d"
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("Invalid duration: %s", s)
+		}
+		return time.Hour * 24 * time.Duration(days), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Setup reminder policy and escalation notifiers
+func setupReminder() error {
+	c := config.Reminder
+	reminderSchedule = make([]time.Duration, 0)
+	for _, s := range c.Schedule {
+		d, err := parseDuration(s)
+		if err != nil {
+			return err
+		}
+		if n := len(reminderSchedule); n > 0 && d <= reminderSchedule[n-1] {
+			return fmt.Errorf("Reminder schedule must be ascending: %s", s)
+		}
+		reminderSchedule = append(reminderSchedule, d)
+	}
+	if c.Sla != "" {
+		d, err := parseDuration(c.Sla)
+		if err != nil {
+			return err
+		}
+		reminderSla = d
+	}
+	for _, name := range c.Escalate {
+		if nt, ok := notifiers[name]; ok {
+			escalationNotifiers[name] = nt
+			continue
+		}
+		nt, err := newNotifier(name)
+		if err != nil {
+			return err
+		}
+		escalationNotifiers[name] = nt
+	}
+	return nil
+}
+
+// Send escalation to escalation notifiers
+func notifyEscalation(n Notification) {
+	if *isSilent {
+		return
+	}
+	n.Escalated = true
+	targets := escalationNotifiers
+	if len(targets) == 0 {
+		targets = notifiers
+	}
+	notifyAll(targets, n)
+}
+
+// Stop reminders once you act on assigned PR or issue after the first notification
+func stopReminder(key []byte, at time.Time) {
+	v, err := db.Get(key, nil)
+	if err != nil || at.IsZero() {
+		return
+	}
+	r := decodeReminder(v)
+	if r.Flags&REMINDER_STOPPED != 0 || uint64(at.Unix()) < r.First {
+		return
+	}
+	r.Flags |= REMINDER_STOPPED
+	db.Put(key, r.encode(), nil)
+}
+
+// Stop reminders of PR which you reviewed
+func stopReminderByReviews(pr PullRequest, reviews []Review) {
+	for _, r := range reviews {
+		if r.User.Login == config.Name && r.State != "PENDING" {
+			stopReminder(assignedKey(pr), r.SubmittedAt)
+		}
+	}
+}
+
+// Stop reminders by comments which you posted
+func stopReminderByComments(key []byte, comments []Comment) {
+	for _, c := range comments {
+		if c.User.Login == config.Name {
+			stopReminder(key, c.CreatedAt)
+		}
+	}
+}
+
+// Loop and remind assigned pull requests in serve and notifications modes
+// These modes don't see assigned PRs without new events, so search them periodically
+func watchReminders() {
+	base := time.Second * time.Duration(config.PollingTime)
+	for {
+		start := time.Now()
+		pollAssigned()
+
+		delay := rateLimit.Delay(base) - time.Since(start)
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+	}
+}
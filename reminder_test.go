@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func setReminderPolicy(schedule []time.Duration, sla time.Duration) {
+	config = &Config{Repeat: 300}
+	reminderSchedule = schedule
+	reminderSla = sla
+}
+
+func TestReminderSchedule(t *testing.T) {
+	setReminderPolicy([]time.Duration{time.Hour, 4 * time.Hour, 24 * time.Hour}, 0)
+	r := newReminder(0)
+
+	steps := []struct {
+		now      uint64
+		expected bool
+		count    uint64
+	}{
+		{100, false, 0},
+		{3600, true, 1},
+		{3700, false, 1},
+		// 4h and 1d entries are passed while sleeping, remind once
+		{100000, true, 3},
+		// Schedule is exhausted
+		{1000000, false, 3},
+	}
+	for _, s := range steps {
+		if actual := r.remind(s.now); actual != s.expected || r.Count != s.count {
+			t.Errorf("remind(%d) = %v (count %d), expected %v (count %d)", s.now, actual, r.Count, s.expected, s.count)
+		}
+	}
+}
+
+func TestReminderRepeat(t *testing.T) {
+	setReminderPolicy(nil, 0)
+	r := newReminder(0)
+
+	steps := []struct {
+		now      uint64
+		expected bool
+	}{
+		{300, false},
+		{301, true},
+		{500, false},
+		{602, true},
+	}
+	for _, s := range steps {
+		if actual := r.remind(s.now); actual != s.expected {
+			t.Errorf("remind(%d) = %v, expected %v", s.now, actual, s.expected)
+		}
+	}
+}
+
+func TestReminderEscalate(t *testing.T) {
+	setReminderPolicy(nil, 48*time.Hour)
+	r := newReminder(0)
+
+	if r.escalate(47 * 3600) {
+		t.Error("Must not escalate before SLA")
+	}
+	if !r.escalate(48 * 3600) {
+		t.Error("Must escalate after SLA")
+	}
+	if r.escalate(49 * 3600) {
+		t.Error("Must escalate only once")
+	}
+
+	setReminderPolicy(nil, 0)
+	r = newReminder(0)
+	if r.escalate(1000000) {
+		t.Error("Must not escalate without SLA")
+	}
+}
+
+func TestReminderStopped(t *testing.T) {
+	setReminderPolicy([]time.Duration{time.Hour}, time.Hour)
+	r := newReminder(0)
+	r.Flags |= REMINDER_STOPPED
+
+	if r.remind(7200) {
+		t.Error("Stopped reminder must not remind")
+	}
+	if r.escalate(7200) {
+		t.Error("Stopped reminder must not escalate")
+	}
+}
+
+func TestReminderEncode(t *testing.T) {
+	r := Reminder{First: 1, Last: 2, Count: 3, Flags: REMINDER_ESCALATED}
+	if decoded := decodeReminder(r.encode()); decoded != r {
+		t.Errorf("Decoded reminder %+v, expected %+v", decoded, r)
+	}
+
+	// Last notified timestamp which is stored by older versions
+	legacy := Reminder{First: 5, Last: 5}.encode()[8:16]
+	if decoded := decodeReminder(legacy); decoded.First != 5 || decoded.Last != 5 {
+		t.Errorf("Unexpected legacy reminder %+v", decoded)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30s": 30 * time.Second,
+		"1h":  time.Hour,
+		"1d":  24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+	}
+	for s, expected := range cases {
+		if actual, err := parseDuration(s); err != nil || actual != expected {
+			t.Errorf("parseDuration(%q) = %s, %v, expected %s", s, actual, err, expected)
+		}
+	}
+	for _, s := range []string{"d", "1w", "xd"} {
+		if _, err := parseDuration(s); err == nil {
+			t.Errorf("parseDuration(%q) must fail", s)
+		}
+	}
+}
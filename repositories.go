@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default interval to refresh expanded repositories (sec)
+const REPOSITORY_REFRESH = 60 * 60
+
+// Expanded repositories which are watched
+type RepositoryList struct {
+	mu          sync.Mutex
+	repos       []string
+	refreshedAt time.Time
+	// Last successful expansion of each wildcard entry
+	expanded map[string][]string
+}
+
+var repositoryList = &RepositoryList{}
+
+// Repositories to watch, expanded wildcard entries
+func watchedRepositories() []string {
+	return repositoryList.Get()
+}
+
+// Get repositories, refresh them when expired
+func (r *RepositoryList) Get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refresh := config.RepositoryRefresh
+	if refresh == 0 {
+		refresh = REPOSITORY_REFRESH
+	}
+	if r.repos == nil || time.Since(r.refreshedAt) > time.Second*time.Duration(refresh) {
+		r.repos, r.expanded = expandRepositories(config.Repositories, config.ExcludeRepositories, r.expanded)
+		r.refreshedAt = time.Now()
+		logger.Passive(fmt.Sprintf("Watching %d repositories", len(r.repos)))
+	}
+	return r.repos
+}
+
+// Check entry has wildcard
+func isRepositoryPattern(entry string) bool {
+	return strings.HasPrefix(entry, "user:") || strings.ContainsAny(entry, "*?[")
+}
+
+// Expand entries into repository names
+// e.g. myorg/*, myorg/api-*, user:someone
+// When expanding an entry fails, its previous expansion is kept
+// Returns repositories and expansion of each wildcard entry
+func expandRepositories(entries, excludes []string, previous map[string][]string) ([]string, map[string][]string) {
+	repos := make([]string, 0)
+	expanded := make(map[string][]string)
+	found := make(map[string]bool)
+	for _, entry := range entries {
+		names := []string{entry}
+		if isRepositoryPattern(entry) {
+			var err error
+			if names, err = expandRepositoryPattern(entry); err != nil {
+				logger.Error(fmt.Sprintf("[ERROR] Cannot expand %s: %s", entry, err.Error()))
+				names = previous[entry]
+			}
+			expanded[entry] = names
+		}
+		for _, name := range names {
+			if found[name] || isExcludedRepository(name, excludes) {
+				continue
+			}
+			found[name] = true
+			repos = append(repos, name)
+		}
+	}
+	return repos, expanded
+}
+
+// Check repository matches exclude patterns
+func isExcludedRepository(name string, excludes []string) bool {
+	for _, pattern := range excludes {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand a wildcard entry via repos API
+func expandRepositoryPattern(entry string) ([]string, error) {
+	var list []Repository
+	var err error
+	pattern := "*"
+	if strings.HasPrefix(entry, "user:") {
+		list, err = listOwnerRepositories("users", strings.TrimPrefix(entry, "user:"))
+	} else {
+		spec := strings.SplitN(entry, "/", 2)
+		if len(spec) != 2 {
+			return nil, fmt.Errorf("Invalid repository pattern")
+		}
+		pattern = spec[1]
+		// Owner may be an user account
+		if list, err = listOwnerRepositories("orgs", spec[0]); err != nil {
+			list, err = listOwnerRepositories("users", spec[0])
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, r := range list {
+		if r.Archived {
+			continue
+		}
+		spec := strings.SplitN(r.FullName, "/", 2)
+		if ok, _ := path.Match(pattern, spec[len(spec)-1]); ok {
+			names = append(names, r.FullName)
+		}
+	}
+	return names, nil
+}
+
+// List repositories of organization or user
+func listOwnerRepositories(kind, owner string) ([]Repository, error) {
+	buf, err := sendListRequest(fmt.Sprintf("%s/%s/%s/repos", apiBase, kind, owner), nil)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Repository, 0)
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpandRepositories(t *testing.T) {
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(502)
+			return
+		}
+		switch r.URL.Path {
+		case "/orgs/myorg/repos":
+			fmt.Fprint(w, `[
+				{"full_name": "myorg/api-server", "archived": false},
+				{"full_name": "myorg/api-legacy", "archived": true},
+				{"full_name": "myorg/web", "archived": false}
+			]`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	config = &Config{}
+	apiBase = server.URL
+	logger = Logger{}
+
+	entries := []string{"myorg/*", "owner/repo"}
+	repos, expanded := expandRepositories(entries, []string{"myorg/web"}, nil)
+	if fmt.Sprint(repos) != "[myorg/api-server owner/repo]" {
+		t.Fatalf("Unexpected repositories: %v", repos)
+	}
+
+	// Keep previous expansion on failure
+	failing = true
+	repos, expanded = expandRepositories(entries, []string{"myorg/web"}, expanded)
+	if fmt.Sprint(repos) != "[myorg/api-server owner/repo]" {
+		t.Errorf("Previous expansion must be kept: %v", repos)
+	}
+	if fmt.Sprint(expanded["myorg/*"]) != "[myorg/api-server myorg/web]" {
+		t.Errorf("Unexpected expansion: %v", expanded)
+	}
+}
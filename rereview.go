@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Your last submitted review on PR
+func myLastReview(reviews []Review) (Review, bool) {
+	var last Review
+	found := false
+	for _, r := range reviews {
+		if r.User.Login != config.Name || r.CommitId == "" {
+			continue
+		}
+		switch r.State {
+		case "APPROVED", "CHANGES_REQUESTED", "COMMENTED":
+			last = r
+			found = true
+		}
+	}
+	return last, found
+}
+
+// Compare URL between two commits
+func compareUrl(repo, base, head string) string {
+	return fmt.Sprintf("%s/%s/compare/%s...%s", webBase, repo, base, head)
+}
+
+// Notify new commits which are pushed after your last review
+// Head SHA at your last review and notified head SHA are stored as "reviewed:notified"
+// Notified once until you review again
+func checkNewCommits(repo string, pr PullRequest, reviews []Review) {
+	if pr.Head.Sha == "" || isAuthored(pr) {
+		return
+	}
+	r, ok := myLastReview(reviews)
+	if !ok {
+		return
+	}
+
+	key := []byte(fmt.Sprintf("reviewed_%s_%d", repo, pr.Number))
+	v, err := db.Get(key, nil)
+	if err != nil {
+		// First seen, only record it
+		notified := ""
+		if r.CommitId != pr.Head.Sha {
+			notified = pr.Head.Sha
+		}
+		db.Put(key, []byte(r.CommitId+":"+notified), nil)
+		return
+	}
+	notified := ""
+	if last := strings.SplitN(string(v), ":", 2); len(last) == 2 && last[0] == r.CommitId {
+		notified = last[1]
+	}
+	if r.CommitId == pr.Head.Sha || notified != "" {
+		db.Put(key, []byte(r.CommitId+":"+notified), nil)
+		return
+	}
+	db.Put(key, []byte(r.CommitId+":"+pr.Head.Sha), nil)
+
+	url := compareUrl(repo, r.CommitId, pr.Head.Sha)
+	logger.Notify(fmt.Sprintf("New commits since your review: #%d %s", pr.Number, url))
+	go notify(Notification{Type: EVENT_NEW_COMMITS, Repo: repo, PullRequest: pr, CommentUrl: url})
+}
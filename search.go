@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Search API result
+type SearchResult struct {
+	TotalCount int          `json:"total_count"`
+	Items      []SearchItem `json:"items"`
+}
+
+// Search API returns pull requests as issue
+// Id is the issue id, so PullRequest is replaced with the resolved one by searchPullRequests
+type SearchItem struct {
+	PullRequest
+	RepositoryUrl string `json:"repository_url"`
+}
+
+// Repository name of the item
+func (s SearchItem) Repo() string {
+	return strings.TrimPrefix(s.RepositoryUrl, apiBase+"/repos/")
+}
+
+// Loop and watch search API
+func watchSearch() {
+	base := time.Second * time.Duration(config.PollingTime)
+	for {
+		start := time.Now()
+		pollSearch()
+
+		delay := rateLimit.Delay(base) - time.Since(start)
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+	}
+}
+
+// Check pull requests assigned to you across all repositories
+func pollAssigned() {
+	items, err := searchPullRequests("assignee:" + config.Name)
+	if err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		return
+	}
+	for _, item := range items {
+		// Your reviews and comments stop reminders
+		checkIssueComment(item.Repo(), item.PullRequest)
+		checkReviewComment(item.Repo(), item.PullRequest)
+		checkReviews(item.Repo(), item.PullRequest)
+		checkAssignee(item.Repo(), item.PullRequest)
+	}
+}
+
+// Find pull requests which relate to you across all repositories
+func pollSearch() {
+	logger.Passive("Search pull requests: " + config.Name)
+
+	pollAssigned()
+
+	if items, err := searchPullRequests("review-requested:" + config.Name); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+	} else {
+		for _, item := range items {
+			checkReviewRequests(item.Repo(), item.PullRequest)
+		}
+	}
+
+	for _, team := range reviewTeams() {
+		if items, err := searchPullRequests("team-review-requested:" + team.FullName()); err != nil {
+			logger.Error("[ERROR] " + err.Error())
+		} else {
+			for _, item := range items {
+				checkReviewRequests(item.Repo(), item.PullRequest)
+			}
+		}
+	}
+
+	if items, err := searchPullRequests("author:" + config.Name); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+	} else {
+		for _, item := range items {
+			checkIssueComment(item.Repo(), item.PullRequest)
+			checkReviewComment(item.Repo(), item.PullRequest)
+			checkReviews(item.Repo(), item.PullRequest)
+			checkCIStatus(item.Repo(), item.PullRequest)
+			checkMergeable(item.Repo(), item.PullRequest)
+		}
+		checkAuthoredSearchResults(items)
+	}
+
+	// PRs which you reviewed drop out of review-requested:, watch new commits on them
+	if items, err := searchPullRequests("reviewed-by:" + config.Name + " -author:" + config.Name); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+	} else {
+		for _, item := range items {
+			checkReviews(item.Repo(), item.PullRequest)
+		}
+	}
+
+	// Only check comments of recently updated PRs
+	since := time.Now().Add(-time.Hour * 24).Format("2006-01-02")
+	if items, err := searchPullRequests("mentions:" + config.Name + " updated:>=" + since); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+	} else {
+		for _, item := range items {
+			checkPullRequestBody(item.Repo(), item.PullRequest)
+			checkIssueComment(item.Repo(), item.PullRequest)
+			checkReviewComment(item.Repo(), item.PullRequest)
+			checkReviews(item.Repo(), item.PullRequest)
+		}
+	}
+
+	logCacheStats()
+}
+
+// Search open pull requests with qualifier
+func searchPullRequests(qualifier string) ([]SearchItem, error) {
+	terms := append([]string{"is:pr", "is:open", qualifier}, config.SearchQualifiers...)
+	query := url.Values{}
+	query.Add("q", strings.Join(terms, " "))
+
+	items := make([]SearchItem, 0)
+	next := withPerPage(fmt.Sprintf("%s/search/issues?%s", apiBase, query.Encode()))
+	for next != "" {
+		resp, buf, err := doRequest("GET", next, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkResponse(resp, buf); err != nil {
+			return nil, err
+		}
+		var result SearchResult
+		if err := json.Unmarshal(buf, &result); err != nil {
+			return nil, err
+		}
+		items = append(items, result.Items...)
+		next = nextPageUrl(resp.Header.Get("Link"))
+	}
+
+	// Resolve pull requests to use the same keys as other sources
+	resolved := make([]SearchItem, 0, len(items))
+	for _, item := range items {
+		pr, err := fetchPullRequest(item.Repo(), item.Number)
+		if err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			continue
+		}
+		item.PullRequest = pr
+		resolved = append(resolved, item)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Webhook receiver configuration
+type ServerConfig struct {
+	Listen string `toml:"listen"`
+	Path   string `toml:"path"`
+	Secret string `toml:"secret"`
+}
+
+// Common webhook event payload
+type WebhookEvent struct {
+	Action            string      `json:"action"`
+	Repository        Repository  `json:"repository"`
+	PullRequest       PullRequest `json:"pull_request"`
+	Issue             *Issue      `json:"issue"`
+	Comment           Comment     `json:"comment"`
+	Review            *Review     `json:"review"`
+	Sender            User        `json:"sender"`
+	RequestedReviewer *Reviewer   `json:"requested_reviewer"`
+	RequestedTeam     *ReviewTeam `json:"requested_team"`
+}
+
+// Received webhook delivery which waits for processing
+type WebhookDelivery struct {
+	Name  string
+	Event WebhookEvent
+}
+
+// Deliveries are acknowledged first, and processed one by one
+// because processing may take longer than GitHub's delivery timeout
+const WEBHOOK_QUEUE_SIZE = 100
+
+// GitHub caps webhook payloads at 25 MB
+const WEBHOOK_MAX_PAYLOAD = 25 * 1024 * 1024
+
+var webhookQueue = make(chan WebhookDelivery, WEBHOOK_QUEUE_SIZE)
+
+// Run HTTP server which receives GitHub webhook
+func serve() error {
+	if config.Server.Secret == "" {
+		return fmt.Errorf("Webhook secret is empty. Put 'secret' in [server] section to verify deliveries.")
+	}
+	listen := config.Server.Listen
+	if listen == "" {
+		listen = ":8080"
+	}
+	path := config.Server.Path
+	if path == "" {
+		path = "/"
+	}
+
+	go processWebhookQueue()
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, handleWebhook)
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	logger.Success(fmt.Sprintf("Listening webhook on %s%s", listen, path))
+	return server.ListenAndServe()
+}
+
+// Verify X-Hub-Signature-256 header
+func verifySignature(signature string, body []byte) bool {
+	if config.Server.Secret == "" {
+		return false
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(config.Server.Secret))
+	mac.Write(body)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
+
+// Handle webhook delivery
+func handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, WEBHOOK_MAX_PAYLOAD))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if !verifySignature(r.Header.Get("X-Hub-Signature-256"), body) {
+		logger.Error("[ERROR] Webhook signature mismatch")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	name := r.Header.Get("X-GitHub-Event")
+	if name == "ping" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		logger.Error("[ERROR] " + err.Error())
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	logger.Passive(fmt.Sprintf("Webhook received: %s %s %s", name, event.Action, event.Repository.FullName))
+	select {
+	case webhookQueue <- WebhookDelivery{Name: name, Event: event}:
+		w.WriteHeader(http.StatusOK)
+	default:
+		// Let GitHub record the failed delivery to redeliver it
+		logger.Error("[ERROR] Webhook queue is full")
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	}
+}
+
+// Process queued deliveries serially to avoid races on notified keys
+func processWebhookQueue() {
+	for d := range webhookQueue {
+		handleWebhookEvent(d.Name, d.Event)
+	}
+}
+
+// Dispatch webhook event to detections
+func handleWebhookEvent(name string, event WebhookEvent) {
+	repo := event.Repository.FullName
+
+	// Issue comment on PR has the issue, resolve PR to use the same keys as polling
+	if name == "issue_comment" && event.Issue != nil && event.Issue.IsPullRequest() && event.Action != "deleted" {
+		pr, err := fetchPullRequest(repo, event.Issue.Number)
+		if err != nil {
+			logger.Error("[ERROR] " + err.Error())
+			return
+		}
+		event.Issue.PullRequest = pr
+	}
+	switch name {
+	case "pull_request", "pull_request_review", "pull_request_review_comment":
+		trackAuthored(repo, event.PullRequest)
+	case "issue_comment":
+		if event.Issue != nil && event.Issue.IsPullRequest() {
+			trackAuthored(repo, event.Issue.PullRequest)
+		}
+	}
+
+	switch name {
+	case "pull_request":
+		// review_request_removed also has requested reviewer
+		if event.Action == "review_requested" && event.RequestedReviewer != nil {
+			handleReviewRequests(repo, event.PullRequest, ReviewRequest{
+				Users: []Reviewer{*event.RequestedReviewer},
+			})
+		}
+		if event.Action == "review_requested" && event.RequestedTeam != nil {
+			handleReviewRequests(repo, event.PullRequest, ReviewRequest{
+				Teams: []ReviewTeam{*event.RequestedTeam},
+			})
+		}
+		if event.Action == "closed" {
+			by := event.Sender
+			if event.PullRequest.MergedBy != nil {
+				by = *event.PullRequest.MergedBy
+			}
+			handleAuthoredClosed(repo, event.PullRequest, by)
+			return
+		}
+		if event.Action == "synchronize" {
+			if reviews, err := fetchReviews(repo, event.PullRequest); err != nil {
+				logger.Error("[ERROR] " + err.Error())
+			} else {
+				checkNewCommits(repo, event.PullRequest, reviews)
+			}
+		}
+		checkPullRequestBody(repo, event.PullRequest)
+		checkAssignee(repo, event.PullRequest)
+	case "pull_request_review":
+		if event.Review != nil {
+			handleReviews(repo, event.PullRequest, []Review{*event.Review})
+		}
+		checkAssignee(repo, event.PullRequest)
+	case "pull_request_review_comment":
+		if event.Action != "deleted" {
+			handleReviewComments(repo, event.PullRequest, []Comment{event.Comment})
+		}
+	case "issue_comment":
+		if event.Issue == nil || event.Action == "deleted" {
+			return
+		}
+		// Issue comment webhook fires for both of pull requests and issues
+		if event.Issue.IsPullRequest() {
+			handleIssueComments(repo, event.Issue.PullRequest, []Comment{event.Comment})
+		} else if _, issues := repositoryOption(repo); issues {
+			handleIssueMentions(repo, *event.Issue, []Comment{event.Comment})
+		}
+	case "issues":
+		if event.Issue == nil || event.Action == "closed" {
+			return
+		}
+		if _, issues := repositoryOption(repo); issues {
+			checkIssueAssignee(repo, *event.Issue)
+			checkIssueLabels(repo, *event.Issue)
+			checkIssueBody(repo, *event.Issue)
+		}
+	}
+}
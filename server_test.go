@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	config = &Config{Server: ServerConfig{Secret: "secret"}}
+	if !verifySignature(signWebhook("secret", body), body) {
+		t.Error("Valid signature must be verified")
+	}
+	if verifySignature(signWebhook("other", body), body) {
+		t.Error("Signature of other secret must be rejected")
+	}
+	if verifySignature("", body) {
+		t.Error("Missing signature must be rejected")
+	}
+
+	config = &Config{}
+	if verifySignature(signWebhook("", body), body) {
+		t.Error("Deliveries must be rejected without secret")
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	config = &Config{Server: ServerConfig{Secret: "secret"}}
+	logger = Logger{}
+
+	cases := []struct {
+		name      string
+		body      []byte
+		signature string
+		status    int
+	}{
+		{"unsigned", []byte(`{}`), "", http.StatusUnauthorized},
+		{"ping", []byte(`{}`), signWebhook("secret", []byte(`{}`)), http.StatusOK},
+		{"too large", make([]byte, WEBHOOK_MAX_PAYLOAD+1), "", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("POST", "/", bytes.NewReader(c.body))
+		req.Header.Set("X-GitHub-Event", "ping")
+		req.Header.Set("X-Hub-Signature-256", c.signature)
+		w := httptest.NewRecorder()
+		handleWebhook(w, req)
+		if w.Code != c.status {
+			t.Errorf("%s: status = %d, expected %d", c.name, w.Code, c.status)
+		}
+	}
+}
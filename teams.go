@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+)
+
+// Team which you belong to
+type Team struct {
+	Id           int    `json:"id"`
+	Slug         string `json:"slug"`
+	Organization User   `json:"organization"`
+}
+
+// Team name as org/slug
+func (t Team) FullName() string {
+	return t.Organization.Login + "/" + t.Slug
+}
+
+// Your teams which are fetched periodically
+type TeamList struct {
+	mu          sync.Mutex
+	teams       map[int]Team
+	refreshedAt time.Time
+}
+
+var teamList = &TeamList{}
+
+// Teams which you belong to
+func myTeams() map[int]Team {
+	return teamList.Get()
+}
+
+// Teams which you belong to and opted in by review_teams config
+func reviewTeams() map[int]Team {
+	teams := make(map[int]Team)
+	if len(config.ReviewTeams) == 0 {
+		return teams
+	}
+	for id, team := range teamList.Get() {
+		if isReviewTeam(team) {
+			teams[id] = team
+		}
+	}
+	return teams
+}
+
+// Get teams, refresh them when expired
+func (t *TeamList) Get() map[int]Team {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	refresh := config.RepositoryRefresh
+	if refresh == 0 {
+		refresh = REPOSITORY_REFRESH
+	}
+	if t.teams != nil && time.Since(t.refreshedAt) <= time.Second*time.Duration(refresh) {
+		return t.teams
+	}
+
+	// Don't retry until next refresh even if failed
+	t.refreshedAt = time.Now()
+	teams, err := fetchTeams()
+	if err != nil {
+		logger.Error("[ERROR] Cannot fetch your teams: " + err.Error())
+		if t.teams == nil {
+			t.teams = make(map[int]Team)
+		}
+		return t.teams
+	}
+	t.teams = make(map[int]Team)
+	for _, team := range teams {
+		t.teams[team.Id] = team
+	}
+	logger.Passive(fmt.Sprintf("You belong to %d teams", len(t.teams)))
+	return t.teams
+}
+
+// Check team is opted in
+func isReviewTeam(team Team) bool {
+	for _, pattern := range config.ReviewTeams {
+		if ok, _ := path.Match(pattern, team.FullName()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch teams which you belong to
+func fetchTeams() ([]Team, error) {
+	buf, err := sendListRequest(apiBase+"/user/teams", nil)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]Team, 0)
+	if err := json.Unmarshal(buf, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}